@@ -0,0 +1,42 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+// azureTranslator talks to an Azure OpenAI deployment, which reuses the
+// chat completions wire format but authenticates via an `api-key` header
+// and addresses a specific deployment and API version instead of a
+// model name.
+type azureTranslator struct {
+	client *chatClient
+}
+
+func newAzureTranslator(endpoint, apiKey, deployment, apiVersion string, verbose bool, timeout time.Duration, maxRetries int, baseDelay time.Duration) *azureTranslator {
+	url := fmt.Sprintf("%s/openai/deployments/%s/chat/completions?api-version=%s",
+		strings.TrimRight(endpoint, "/"), deployment, apiVersion)
+
+	return &azureTranslator{
+		client: newChatClient(url, map[string]string{"api-key": apiKey}, "", verbose, timeout, maxRetries, baseDelay),
+	}
+}
+
+func (t *azureTranslator) Translate(ctx context.Context, fromLang, toLang, text string) (string, error) {
+	return t.client.translate(ctx, fromLang, toLang, text)
+}
+
+func (t *azureTranslator) TranslateStream(ctx context.Context, fromLang, toLang, text string, w io.Writer) (string, error) {
+	return t.client.translateStream(ctx, fromLang, toLang, text, w)
+}
+
+func (t *azureTranslator) ChatTranslate(ctx context.Context, history []message) (string, error) {
+	return t.client.chatOnce(ctx, history)
+}
+
+func (t *azureTranslator) ChatTranslateStream(ctx context.Context, history []message, w io.Writer) (string, error) {
+	return t.client.chatStream(ctx, history, w)
+}
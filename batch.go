@@ -0,0 +1,202 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+const defaultChunkTokenBudget = 2000
+
+// runBatchTranslation translates one or more files in place (or to out,
+// for a single -F file), detecting each file's format by extension and
+// translating only its human-readable segments. ctx bounds every
+// translation request issued along the way.
+func runBatchTranslation(ctx context.Context, translator Translator, fromLang, toLang, file, out, glob string, chunkTokens int, verbose bool) error {
+	if glob != "" {
+		return translateGlob(ctx, translator, fromLang, toLang, glob, chunkTokens, verbose)
+	}
+
+	return translateFile(ctx, translator, fromLang, toLang, file, out, chunkTokens, verbose)
+}
+
+// translateGlob recursively walks the working directory, translating
+// every file whose base name matches pattern (a plain filepath.Match
+// pattern, e.g. "*.md" — it does not support "**").
+func translateGlob(ctx context.Context, translator Translator, fromLang, toLang, pattern string, chunkTokens int, verbose bool) error {
+	var matches []string
+	err := filepath.WalkDir(".", func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		if ok, _ := filepath.Match(pattern, filepath.Base(path)); ok {
+			matches = append(matches, path)
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to walk directory tree: %w", err)
+	}
+
+	if len(matches) == 0 {
+		return fmt.Errorf("no files matched glob %q", pattern)
+	}
+
+	for _, path := range matches {
+		if verbose {
+			fmt.Printf("Translating %s\n", path)
+		}
+		if err := translateFile(ctx, translator, fromLang, toLang, path, "", chunkTokens, verbose); err != nil {
+			return fmt.Errorf("%s: %w", path, err)
+		}
+	}
+
+	return nil
+}
+
+func translateFile(ctx context.Context, translator Translator, fromLang, toLang, path, out string, chunkTokens int, verbose bool) error {
+	format, err := formatForPath(path)
+	if err != nil {
+		return err
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", path, err)
+	}
+
+	segs, err := format.Parse(data)
+	if err != nil {
+		return fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+
+	if err := translateSegments(ctx, translator, fromLang, toLang, segs, chunkTokens, verbose); err != nil {
+		return fmt.Errorf("failed to translate %s: %w", path, err)
+	}
+
+	rendered, err := format.Render(segs)
+	if err != nil {
+		return fmt.Errorf("failed to render %s: %w", path, err)
+	}
+
+	destination := path
+	if out != "" {
+		destination = out
+	}
+
+	if err := os.WriteFile(destination, rendered, 0o644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", destination, err)
+	}
+
+	return nil
+}
+
+// segmentDelimiter separates batched segments within a single
+// translation request. A model is not guaranteed to preserve it
+// character-for-character, so translateSegments falls back to
+// translating the chunk's segments individually whenever the returned
+// part count doesn't match.
+const segmentDelimiter = "\n§§§\n"
+
+// batchSystemPrompt replaces translatorSystemPrompt for chunked batch
+// requests: it tells the model explicitly to keep the segment delimiter
+// unchanged, since translateSegments depends on splitting the response
+// back apart by it. Backends that can't carry a system prompt (DeepL)
+// don't get this instruction and so lean more on the per-segment
+// fallback below.
+var batchSystemPrompt = fmt.Sprintf(
+	"You are a translator that only gives the translated text. "+
+		"The input contains multiple segments separated by a line that reads exactly %q. "+
+		"Reproduce that separator line unchanged, on its own line, in the same position between the corresponding translated segments. Do not translate or alter the separator itself.",
+	strings.TrimSpace(segmentDelimiter),
+)
+
+// translateChunk translates a (possibly multi-segment) chunk of batched
+// text. If translator supports carrying a system prompt, it uses
+// batchSystemPrompt to ask the model to preserve segmentDelimiter;
+// otherwise it falls back to a plain Translate call.
+func translateChunk(ctx context.Context, translator Translator, fromLang, toLang, text string) (string, error) {
+	historyTranslator, ok := translator.(chatHistoryTranslator)
+	if !ok {
+		return translator.Translate(ctx, fromLang, toLang, text)
+	}
+
+	history := []message{
+		{Role: "system", Content: batchSystemPrompt},
+		{Role: "user", Content: fmt.Sprintf("Translate this from %s to %s:\n%s", fromLang, toLang, text)},
+	}
+	return historyTranslator.ChatTranslate(ctx, history)
+}
+
+// translateSegments batches translatable segments into chunks bounded by
+// chunkTokens (approximated as 4 characters per token) and translates
+// each chunk with a single request, splitting the result back onto the
+// original segments by segmentDelimiter.
+func translateSegments(ctx context.Context, translator Translator, fromLang, toLang string, segs []segment, chunkTokens int, verbose bool) error {
+	if chunkTokens <= 0 {
+		chunkTokens = defaultChunkTokenBudget
+	}
+	budgetChars := chunkTokens * 4
+
+	var chunkIdxs []int
+	var chunkText strings.Builder
+	chunkChars := 0
+
+	flush := func() error {
+		if len(chunkIdxs) == 0 {
+			return nil
+		}
+
+		translated, err := translateChunk(ctx, translator, fromLang, toLang, chunkText.String())
+		if err != nil {
+			return err
+		}
+
+		parts := strings.Split(translated, strings.TrimSpace(segmentDelimiter))
+		if len(parts) != len(chunkIdxs) {
+			if verbose {
+				fmt.Printf("Warning: chunk boundary mismatch (%d segments, %d parts); translating individually\n", len(chunkIdxs), len(parts))
+			}
+			for _, idx := range chunkIdxs {
+				t, err := translator.Translate(ctx, fromLang, toLang, segs[idx].Text)
+				if err != nil {
+					return err
+				}
+				segs[idx].Text = strings.TrimSpace(t)
+			}
+		} else {
+			for i, idx := range chunkIdxs {
+				segs[idx].Text = strings.TrimSpace(parts[i])
+			}
+		}
+
+		chunkIdxs = chunkIdxs[:0]
+		chunkText.Reset()
+		chunkChars = 0
+		return nil
+	}
+
+	for i := range segs {
+		if !segs[i].Translatable || strings.TrimSpace(segs[i].Text) == "" {
+			continue
+		}
+
+		if chunkChars > 0 && chunkChars+len(segs[i].Text) > budgetChars {
+			if err := flush(); err != nil {
+				return err
+			}
+		}
+
+		if len(chunkIdxs) > 0 {
+			chunkText.WriteString(segmentDelimiter)
+		}
+		chunkText.WriteString(segs[i].Text)
+		chunkChars += len(segs[i].Text)
+		chunkIdxs = append(chunkIdxs, i)
+	}
+
+	return flush()
+}
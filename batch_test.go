@@ -0,0 +1,134 @@
+package main
+
+import (
+	"context"
+	"io"
+	"strings"
+	"testing"
+)
+
+// fakeTranslator uppercases text, joining batched segments back together
+// by the same delimiter it was given so translateSegments's happy path
+// can be exercised without a real backend.
+type fakeTranslator struct {
+	calls int
+}
+
+func (f *fakeTranslator) Translate(ctx context.Context, fromLang, toLang, text string) (string, error) {
+	f.calls++
+	return strings.ToUpper(text), nil
+}
+
+func (f *fakeTranslator) TranslateStream(ctx context.Context, fromLang, toLang, text string, w io.Writer) (string, error) {
+	result, err := f.Translate(ctx, fromLang, toLang, text)
+	if err != nil {
+		return "", err
+	}
+	io.WriteString(w, result)
+	return result, nil
+}
+
+// mismatchTranslator returns a translation with a different number of
+// delimiter-separated parts than it was given, forcing the per-segment
+// fallback path.
+type mismatchTranslator struct {
+	fakeTranslator
+}
+
+func (f *mismatchTranslator) Translate(ctx context.Context, fromLang, toLang, text string) (string, error) {
+	f.calls++
+	if strings.Contains(text, strings.TrimSpace(segmentDelimiter)) {
+		return strings.ToUpper(strings.ReplaceAll(text, strings.TrimSpace(segmentDelimiter), " ")), nil
+	}
+	return strings.ToUpper(text), nil
+}
+
+func TestTranslateSegmentsHappyPath(t *testing.T) {
+	segs := []segment{
+		{Text: "hello", Translatable: true},
+		{Text: "world", Translatable: true},
+		{Text: "---", Translatable: false},
+	}
+
+	ft := &fakeTranslator{}
+	if err := translateSegments(context.Background(), ft, "en", "fr", segs, defaultChunkTokenBudget, false); err != nil {
+		t.Fatalf("translateSegments: %v", err)
+	}
+
+	if segs[0].Text != "HELLO" || segs[1].Text != "WORLD" {
+		t.Errorf("unexpected translated segments: %+v", segs)
+	}
+	if segs[2].Text != "---" {
+		t.Errorf("non-translatable segment was modified: %+v", segs[2])
+	}
+	if ft.calls != 1 {
+		t.Errorf("expected segments to be batched into a single request, got %d calls", ft.calls)
+	}
+}
+
+func TestTranslateSegmentsFallsBackOnDelimiterMismatch(t *testing.T) {
+	segs := []segment{
+		{Text: "hello", Translatable: true},
+		{Text: "world", Translatable: true},
+	}
+
+	mt := &mismatchTranslator{}
+	if err := translateSegments(context.Background(), mt, "en", "fr", segs, defaultChunkTokenBudget, false); err != nil {
+		t.Fatalf("translateSegments: %v", err)
+	}
+
+	if segs[0].Text != "HELLO" || segs[1].Text != "WORLD" {
+		t.Errorf("expected per-segment fallback to still translate correctly, got %+v", segs)
+	}
+	// One batched call plus one fallback call per segment.
+	if mt.calls != 1+len(segs) {
+		t.Errorf("expected %d calls (1 batch + %d fallback), got %d", 1+len(segs), len(segs), mt.calls)
+	}
+}
+
+func TestTranslateSegmentsRespectsChunkBudget(t *testing.T) {
+	segs := []segment{
+		{Text: strings.Repeat("a", 20), Translatable: true},
+		{Text: strings.Repeat("b", 20), Translatable: true},
+	}
+
+	ft := &fakeTranslator{}
+	// Budget of 1 token (~4 chars) forces each segment into its own chunk.
+	if err := translateSegments(context.Background(), ft, "en", "fr", segs, 1, false); err != nil {
+		t.Fatalf("translateSegments: %v", err)
+	}
+
+	if ft.calls != 2 {
+		t.Errorf("expected 2 separate chunk requests under a tight budget, got %d", ft.calls)
+	}
+}
+
+func TestTranslateChunkUsesBatchSystemPromptWhenAvailable(t *testing.T) {
+	ct := &capturingChatTranslator{}
+	_, err := translateChunk(context.Background(), ct, "en", "fr", "a"+segmentDelimiter+"b")
+	if err != nil {
+		t.Fatalf("translateChunk: %v", err)
+	}
+
+	if len(ct.history) == 0 || ct.history[0].Role != "system" || ct.history[0].Content != batchSystemPrompt {
+		t.Errorf("expected the batch system prompt to be sent as the first message, got %+v", ct.history)
+	}
+}
+
+// capturingChatTranslator implements both Translator and
+// chatHistoryTranslator, recording the history it was asked to translate.
+type capturingChatTranslator struct {
+	fakeTranslator
+	history []message
+}
+
+func (c *capturingChatTranslator) ChatTranslate(ctx context.Context, history []message) (string, error) {
+	c.history = history
+	return "ok", nil
+}
+
+func (c *capturingChatTranslator) ChatTranslateStream(ctx context.Context, history []message, w io.Writer) (string, error) {
+	c.history = history
+	io.WriteString(w, "ok")
+	return "ok", nil
+}
@@ -0,0 +1,233 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+)
+
+type message struct {
+	Role    string `json:"role"`
+	Content string `json:"content"`
+}
+
+type chatRequest struct {
+	Model    string    `json:"model"`
+	Messages []message `json:"messages"`
+	Stream   bool      `json:"stream,omitempty"`
+}
+
+type chatResponse struct {
+	Choices []struct {
+		Message message `json:"message"`
+	} `json:"choices"`
+	Error struct {
+		Message string `json:"message"`
+		Type    string `json:"type"`
+	} `json:"error"`
+}
+
+type chatStreamChunk struct {
+	Choices []struct {
+		Delta struct {
+			Content string `json:"content"`
+		} `json:"delta"`
+	} `json:"choices"`
+	Error struct {
+		Message string `json:"message"`
+		Type    string `json:"type"`
+	} `json:"error"`
+}
+
+const translatorSystemPrompt = "You are a translator that only gives the translated text"
+
+// chatClient speaks the OpenAI chat-completions wire format. OpenAI,
+// Azure OpenAI, and OpenAI-compatible servers (LocalAI, Ollama, vLLM)
+// all implement this protocol, differing only in url, headers, and
+// model naming, so they share this one implementation.
+type chatClient struct {
+	url     string
+	headers map[string]string
+	model   string
+	verbose bool
+
+	httpClient *http.Client
+	retry      retryConfig
+}
+
+// newChatClient builds a chatClient with an *http.Client bounded by
+// timeout and a retry policy of up to maxRetries retries after the first
+// attempt, with exponential backoff starting at baseDelay.
+func newChatClient(url string, headers map[string]string, model string, verbose bool, timeout time.Duration, maxRetries int, baseDelay time.Duration) *chatClient {
+	return &chatClient{
+		url:        url,
+		headers:    headers,
+		model:      model,
+		verbose:    verbose,
+		httpClient: &http.Client{Timeout: timeout},
+		retry:      retryConfig{MaxRetries: maxRetries, BaseDelay: baseDelay, Verbose: verbose},
+	}
+}
+
+func buildChatMessages(fromLang, toLang, text string) []message {
+	return []message{
+		{Role: "system", Content: translatorSystemPrompt},
+		{Role: "user", Content: fmt.Sprintf("Translate this from %s to %s: %s", fromLang, toLang, text)},
+	}
+}
+
+func (c *chatClient) newRequest(ctx context.Context, chatReq chatRequest) (*http.Request, error) {
+	jsonPayload, err := json.Marshal(chatReq)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal JSON payload: %w", err)
+	}
+
+	if c.verbose {
+		log.Printf("Request Payload: %s\n", string(jsonPayload))
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", c.url, bytes.NewBuffer(jsonPayload))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create new HTTP request: %w", err)
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	for k, v := range c.headers {
+		req.Header.Set(k, v)
+	}
+
+	return req, nil
+}
+
+// translate sends a one-shot (non-streaming) translation request.
+func (c *chatClient) translate(ctx context.Context, fromLang, toLang, text string) (string, error) {
+	return c.chatOnce(ctx, buildChatMessages(fromLang, toLang, text))
+}
+
+// translateStream sends a streaming translation request, writing each
+// token to w as it arrives.
+func (c *chatClient) translateStream(ctx context.Context, fromLang, toLang, text string, w io.Writer) (string, error) {
+	return c.chatStream(ctx, buildChatMessages(fromLang, toLang, text), w)
+}
+
+// chatOnce sends the full message history (as built up by the
+// interactive REPL, for instance) and returns the assistant's reply.
+func (c *chatClient) chatOnce(ctx context.Context, history []message) (string, error) {
+	resp, err := doWithRetry(ctx, c.httpClient, c.retry, func(ctx context.Context) (*http.Request, error) {
+		return c.newRequest(ctx, chatRequest{Model: c.model, Messages: history})
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to execute HTTP request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", fmt.Errorf("failed to read response body: %w", err)
+	}
+
+	if c.verbose {
+		log.Printf("Response Status: %s\n", resp.Status)
+		log.Printf("Response Body: %s\n", string(body))
+	}
+
+	var chatResp chatResponse
+	if err := json.Unmarshal(body, &chatResp); err != nil {
+		return "", fmt.Errorf("failed to unmarshal response JSON: %w", err)
+	}
+
+	if chatResp.Error.Message != "" {
+		if c.verbose {
+			log.Printf("API Error: %s (Type: %s)\n", chatResp.Error.Message, chatResp.Error.Type)
+		}
+		return "", errors.New(chatResp.Error.Message)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("unexpected status code from chat API: %d", resp.StatusCode)
+	}
+
+	if len(chatResp.Choices) > 0 {
+		return strings.TrimSpace(chatResp.Choices[0].Message.Content), nil
+	}
+
+	return "", errors.New("no translation found in response")
+}
+
+// chatStream sends the full message history with streaming enabled,
+// writing each token to w as it arrives.
+func (c *chatClient) chatStream(ctx context.Context, history []message, w io.Writer) (string, error) {
+	resp, err := doWithRetry(ctx, c.httpClient, c.retry, func(ctx context.Context) (*http.Request, error) {
+		return c.newRequest(ctx, chatRequest{Model: c.model, Messages: history, Stream: true})
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to execute HTTP request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		if c.verbose {
+			log.Printf("Response Status: %s\n", resp.Status)
+			log.Printf("Response Body: %s\n", string(body))
+		}
+		return "", fmt.Errorf("unexpected status code from chat API: %d: %s", resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+
+	var builder strings.Builder
+	scanner := bufio.NewScanner(resp.Body)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || !strings.HasPrefix(line, "data:") {
+			continue
+		}
+
+		payload := strings.TrimSpace(strings.TrimPrefix(line, "data:"))
+		if payload == "[DONE]" {
+			break
+		}
+
+		var chunk chatStreamChunk
+		if err := json.Unmarshal([]byte(payload), &chunk); err != nil {
+			return builder.String(), fmt.Errorf("failed to unmarshal stream chunk: %w", err)
+		}
+
+		if chunk.Error.Message != "" {
+			if c.verbose {
+				log.Printf("API Error: %s (Type: %s)\n", chunk.Error.Message, chunk.Error.Type)
+			}
+			return builder.String(), errors.New(chunk.Error.Message)
+		}
+
+		if len(chunk.Choices) == 0 {
+			continue
+		}
+
+		token := chunk.Choices[0].Delta.Content
+		if token == "" {
+			continue
+		}
+
+		builder.WriteString(token)
+		fmt.Fprint(w, token)
+	}
+
+	if err := scanner.Err(); err != nil {
+		return builder.String(), fmt.Errorf("failed to read response stream: %w", err)
+	}
+
+	result := strings.TrimSpace(builder.String())
+	if result == "" {
+		return "", errors.New("no translation found in response")
+	}
+
+	return result, nil
+}
@@ -0,0 +1,144 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func newTestChatClient(url string) *chatClient {
+	return newChatClient(url, nil, "test-model", false, 0, 0, 0)
+}
+
+func TestChatStreamAccumulatesTokensUntilDone(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		frames := []string{
+			`{"choices":[{"delta":{"content":"Ol"}}]}`,
+			`{"choices":[{"delta":{"content":"á"}}]}`,
+			`{"choices":[{"delta":{"content":" mundo"}}]}`,
+		}
+		for _, f := range frames {
+			fmt.Fprintf(w, "data: %s\n\n", f)
+		}
+		fmt.Fprint(w, "data: [DONE]\n\n")
+	}))
+	defer srv.Close()
+
+	c := newTestChatClient(srv.URL)
+	var buf strings.Builder
+	result, err := c.chatStream(context.Background(), []message{{Role: "user", Content: "hi"}}, &buf)
+	if err != nil {
+		t.Fatalf("chatStream: %v", err)
+	}
+
+	if result != "Olá mundo" {
+		t.Errorf("result = %q, want %q", result, "Olá mundo")
+	}
+	if buf.String() != "Olá mundo" {
+		t.Errorf("tokens written to w = %q, want %q", buf.String(), "Olá mundo")
+	}
+}
+
+func TestChatStreamIgnoresFramesAfterDone(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, "data: %s\n\n", `{"choices":[{"delta":{"content":"hi"}}]}`)
+		fmt.Fprint(w, "data: [DONE]\n\n")
+		fmt.Fprintf(w, "data: %s\n\n", `{"choices":[{"delta":{"content":"ignored"}}]}`)
+	}))
+	defer srv.Close()
+
+	c := newTestChatClient(srv.URL)
+	var buf strings.Builder
+	result, err := c.chatStream(context.Background(), []message{{Role: "user", Content: "hi"}}, &buf)
+	if err != nil {
+		t.Fatalf("chatStream: %v", err)
+	}
+	if result != "hi" {
+		t.Errorf("result = %q, want %q", result, "hi")
+	}
+}
+
+func TestChatStreamReturnsMidStreamError(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, "data: %s\n\n", `{"choices":[{"delta":{"content":"partial "}}]}`)
+		fmt.Fprintf(w, "data: %s\n\n", `{"error":{"message":"rate limited","type":"rate_limit"}}`)
+	}))
+	defer srv.Close()
+
+	c := newTestChatClient(srv.URL)
+	var buf strings.Builder
+	result, err := c.chatStream(context.Background(), []message{{Role: "user", Content: "hi"}}, &buf)
+	if err == nil {
+		t.Fatal("expected an error from a mid-stream error frame")
+	}
+	if err.Error() != "rate limited" {
+		t.Errorf("err = %v, want %q", err, "rate limited")
+	}
+	if result != "partial " {
+		t.Errorf("expected tokens seen before the error to still be returned, got %q", result)
+	}
+}
+
+func TestChatStreamSkipsBlankAndNonDataLines(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, ": a comment line\n\n")
+		fmt.Fprintf(w, "data: %s\n\n", `{"choices":[{"delta":{"content":"ok"}}]}`)
+		fmt.Fprint(w, "data: [DONE]\n\n")
+	}))
+	defer srv.Close()
+
+	c := newTestChatClient(srv.URL)
+	result, err := c.chatStream(context.Background(), []message{{Role: "user", Content: "hi"}}, io.Discard)
+	if err != nil {
+		t.Fatalf("chatStream: %v", err)
+	}
+	if result != "ok" {
+		t.Errorf("result = %q, want %q", result, "ok")
+	}
+}
+
+func TestChatStreamRejectsNon2xxStatus(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+		fmt.Fprint(w, `{"error":{"message":"invalid key"}}`)
+	}))
+	defer srv.Close()
+
+	c := newTestChatClient(srv.URL)
+	if _, err := c.chatStream(context.Background(), []message{{Role: "user", Content: "hi"}}, io.Discard); err == nil {
+		t.Fatal("expected an error for a non-2xx response")
+	}
+}
+
+func TestChatOnceReturnsAssistantReply(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"choices":[{"message":{"role":"assistant","content":"bonjour"}}]}`)
+	}))
+	defer srv.Close()
+
+	c := newTestChatClient(srv.URL)
+	result, err := c.chatOnce(context.Background(), []message{{Role: "user", Content: "hello"}})
+	if err != nil {
+		t.Fatalf("chatOnce: %v", err)
+	}
+	if result != "bonjour" {
+		t.Errorf("result = %q, want %q", result, "bonjour")
+	}
+}
+
+func TestChatOnceReturnsAPIError(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"error":{"message":"invalid request","type":"invalid_request_error"}}`)
+	}))
+	defer srv.Close()
+
+	c := newTestChatClient(srv.URL)
+	if _, err := c.chatOnce(context.Background(), []message{{Role: "user", Content: "hello"}}); err == nil || err.Error() != "invalid request" {
+		t.Fatalf("chatOnce error = %v, want %q", err, "invalid request")
+	}
+}
@@ -0,0 +1,43 @@
+package main
+
+import (
+	"context"
+	"io"
+	"strings"
+	"time"
+)
+
+// compatibleTranslator talks to any self-hosted server that mirrors the
+// OpenAI chat completions API, such as LocalAI, Ollama, or vLLM. It only
+// differs from openAITranslator in its base URL and optional auth.
+type compatibleTranslator struct {
+	client *chatClient
+}
+
+func newCompatibleTranslator(baseURL, apiToken, model string, verbose bool, timeout time.Duration, maxRetries int, baseDelay time.Duration) *compatibleTranslator {
+	headers := map[string]string{}
+	if apiToken != "" {
+		headers["Authorization"] = "Bearer " + apiToken
+	}
+
+	url := strings.TrimRight(baseURL, "/") + "/v1/chat/completions"
+	return &compatibleTranslator{
+		client: newChatClient(url, headers, model, verbose, timeout, maxRetries, baseDelay),
+	}
+}
+
+func (t *compatibleTranslator) Translate(ctx context.Context, fromLang, toLang, text string) (string, error) {
+	return t.client.translate(ctx, fromLang, toLang, text)
+}
+
+func (t *compatibleTranslator) TranslateStream(ctx context.Context, fromLang, toLang, text string, w io.Writer) (string, error) {
+	return t.client.translateStream(ctx, fromLang, toLang, text, w)
+}
+
+func (t *compatibleTranslator) ChatTranslate(ctx context.Context, history []message) (string, error) {
+	return t.client.chatOnce(ctx, history)
+}
+
+func (t *compatibleTranslator) ChatTranslateStream(ctx context.Context, history []message, w io.Writer) (string, error) {
+	return t.client.chatStream(ctx, history, w)
+}
@@ -0,0 +1,57 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"gopkg.in/yaml.v2"
+)
+
+// providerConfig holds the per-provider settings read from the
+// `providers:` map in the config file. Not every field applies to every
+// provider: Deployment and APIVersion are Azure-specific, for instance.
+type providerConfig struct {
+	APIToken   string `yaml:"api_token"`
+	BaseURL    string `yaml:"base_url"`
+	Model      string `yaml:"model"`
+	Deployment string `yaml:"deployment"`
+	APIVersion string `yaml:"api_version"`
+}
+
+// serveConfig holds settings for the `translate-cli serve` subcommand.
+type serveConfig struct {
+	BearerToken string `yaml:"bearer_token"`
+}
+
+type config struct {
+	APIToken         string                    `yaml:"api_token"`
+	EnableHistory    bool                      `yaml:"enable_history"`
+	DefaultProvider  string                    `yaml:"default_provider"`
+	Providers        map[string]providerConfig `yaml:"providers"`
+	Serve            serveConfig               `yaml:"serve"`
+	TimeoutSeconds   int                       `yaml:"timeout_seconds"`
+	MaxRetries       int                       `yaml:"max_retries"`
+	RetryBaseDelayMS int                       `yaml:"retry_base_delay_ms"`
+}
+
+func loadConfigFile() (config, error) {
+	var cfg config
+
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return cfg, fmt.Errorf("failed to retrieve home directory: %w", err)
+	}
+
+	configPath := filepath.Join(home, ".config", "openapi", "secret.yml")
+	data, err := os.ReadFile(configPath)
+	if err != nil {
+		return cfg, fmt.Errorf("failed to read config file: %w", err)
+	}
+
+	if err := yaml.Unmarshal(data, &cfg); err != nil {
+		return cfg, fmt.Errorf("failed to parse config file: %w", err)
+	}
+
+	return cfg, nil
+}
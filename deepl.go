@@ -0,0 +1,114 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const defaultDeepLURL = "https://api-free.deepl.com/v2/translate"
+
+// deepLTranslator calls the DeepL translate API, which takes
+// form-encoded text/source_lang/target_lang instead of the chat
+// completions protocol the other backends share.
+type deepLTranslator struct {
+	apiKey  string
+	baseURL string
+	verbose bool
+
+	httpClient *http.Client
+	retry      retryConfig
+}
+
+func newDeepLTranslator(apiKey, baseURL string, verbose bool, timeout time.Duration, maxRetries int, baseDelay time.Duration) *deepLTranslator {
+	if baseURL == "" {
+		baseURL = defaultDeepLURL
+	}
+	return &deepLTranslator{
+		apiKey:     apiKey,
+		baseURL:    baseURL,
+		verbose:    verbose,
+		httpClient: &http.Client{Timeout: timeout},
+		retry:      retryConfig{MaxRetries: maxRetries, BaseDelay: baseDelay, Verbose: verbose},
+	}
+}
+
+type deepLResponse struct {
+	Translations []struct {
+		Text string `json:"text"`
+	} `json:"translations"`
+	Message string `json:"message"`
+}
+
+func (t *deepLTranslator) Translate(ctx context.Context, fromLang, toLang, text string) (string, error) {
+	form := url.Values{
+		"text":        {text},
+		"source_lang": {strings.ToUpper(fromLang)},
+		"target_lang": {strings.ToUpper(toLang)},
+	}
+
+	if t.verbose {
+		log.Printf("Request Payload: %s\n", form.Encode())
+	}
+
+	resp, err := doWithRetry(ctx, t.httpClient, t.retry, func(ctx context.Context) (*http.Request, error) {
+		req, err := http.NewRequestWithContext(ctx, "POST", t.baseURL, strings.NewReader(form.Encode()))
+		if err != nil {
+			return nil, fmt.Errorf("failed to create new HTTP request: %w", err)
+		}
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		req.Header.Set("Authorization", "DeepL-Auth-Key "+t.apiKey)
+		return req, nil
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to execute HTTP request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", fmt.Errorf("failed to read response body: %w", err)
+	}
+
+	if t.verbose {
+		log.Printf("Response Status: %s\n", resp.Status)
+		log.Printf("Response Body: %s\n", string(body))
+	}
+
+	var deeplResp deepLResponse
+	if err := json.Unmarshal(body, &deeplResp); err != nil {
+		return "", fmt.Errorf("failed to unmarshal response JSON: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		if deeplResp.Message != "" {
+			return "", fmt.Errorf("DeepL API error: %s", deeplResp.Message)
+		}
+		return "", fmt.Errorf("unexpected status code from DeepL API: %d", resp.StatusCode)
+	}
+
+	if len(deeplResp.Translations) > 0 {
+		return strings.TrimSpace(deeplResp.Translations[0].Text), nil
+	}
+
+	return "", fmt.Errorf("no translation found in response")
+}
+
+// TranslateStream falls back to a single, non-streamed request: the
+// DeepL translate API has no token-streaming mode, so the whole result
+// is written to w at once.
+func (t *deepLTranslator) TranslateStream(ctx context.Context, fromLang, toLang, text string, w io.Writer) (string, error) {
+	result, err := t.Translate(ctx, fromLang, toLang, text)
+	if err != nil {
+		return "", err
+	}
+
+	fmt.Fprint(w, result)
+	return result, nil
+}
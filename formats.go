@@ -0,0 +1,57 @@
+package main
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+)
+
+// segment is one unit of a parsed file: either text to hand to the
+// translator, or a structural fragment (timestamps, code, markup) that
+// must be re-emitted untouched.
+type segment struct {
+	Text         string
+	Translatable bool
+
+	// spans holds the original text of any inline spans (code, URLs,
+	// link targets) that Text replaced with placeholders before
+	// translation. Only markdownFormat currently populates this.
+	spans []string
+
+	// original holds the line as parsed, before translation, so Render
+	// can fall back to it if a translated Text no longer carries a
+	// placeholder for every span in spans. Only markdownFormat
+	// currently populates this.
+	original string
+
+	// tag holds format-specific metadata needed to re-render Text, such
+	// as gettextFormat's "msgstr" vs "msgstr[N]" prefix.
+	tag string
+}
+
+// fileFormat parses a file into segments and renders a (possibly
+// translated) segment list back into the file's original format.
+type fileFormat interface {
+	Parse(data []byte) ([]segment, error)
+	Render(segments []segment) ([]byte, error)
+}
+
+// formatForPath picks the fileFormat for path based on its extension.
+func formatForPath(path string) (fileFormat, error) {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".srt":
+		return srtFormat{}, nil
+	case ".vtt":
+		return vttFormat{}, nil
+	case ".md", ".markdown":
+		return markdownFormat{}, nil
+	case ".po":
+		return gettextFormat{}, nil
+	case ".json":
+		return &jsonTreeFormat{}, nil
+	case ".yaml", ".yml":
+		return &yamlTreeFormat{}, nil
+	default:
+		return nil, fmt.Errorf("unsupported file format: %s", path)
+	}
+}
@@ -0,0 +1,24 @@
+package main
+
+import "testing"
+
+func TestFormatForPathDispatchesByExtension(t *testing.T) {
+	cases := map[string]bool{
+		"a.srt":      true,
+		"a.vtt":      true,
+		"a.md":       true,
+		"a.markdown": true,
+		"a.po":       true,
+		"a.json":     true,
+		"a.yaml":     true,
+		"a.yml":      true,
+		"a.txt":      false,
+	}
+
+	for path, wantOK := range cases {
+		_, err := formatForPath(path)
+		if (err == nil) != wantOK {
+			t.Errorf("formatForPath(%q): err = %v, want ok = %v", path, err, wantOK)
+		}
+	}
+}
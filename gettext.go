@@ -0,0 +1,73 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// gettextFormat translates the msgstr half of each msgid/msgstr pair in
+// a .po file, one translatable unit per non-empty msgstr or msgstr[N]
+// (plural form) line. Entries spanning multiple quoted-string lines are
+// not supported.
+type gettextFormat struct{}
+
+var msgstrPluralRe = regexp.MustCompile(`^msgstr(\[\d+\])?\s`)
+
+func (gettextFormat) Parse(data []byte) ([]segment, error) {
+	lines := strings.Split(string(data), "\n")
+	segs := make([]segment, 0, len(lines))
+
+	for i, line := range lines {
+		trimmed := strings.TrimSpace(line)
+		prefix := msgstrPluralRe.FindString(trimmed)
+		if prefix == "" {
+			segs = append(segs, segment{Text: line, Translatable: false})
+			continue
+		}
+
+		tag := strings.TrimSpace(prefix)
+		quoted := strings.TrimSpace(strings.TrimPrefix(trimmed, tag))
+		text, err := strconv.Unquote(quoted)
+		if err != nil {
+			return nil, fmt.Errorf("malformed msgstr at line %d: %w", i+1, err)
+		}
+
+		if text == "" {
+			segs = append(segs, segment{Text: line, Translatable: false})
+			continue
+		}
+
+		segs = append(segs, segment{Text: text, Translatable: true, tag: tag})
+	}
+
+	return segs, nil
+}
+
+func (gettextFormat) Render(segs []segment) ([]byte, error) {
+	var buf bytes.Buffer
+
+	for _, s := range segs {
+		if s.Translatable {
+			tag := "msgstr"
+			if s.tag != "" {
+				tag = s.tag
+			}
+			buf.WriteString(tag)
+			buf.WriteByte(' ')
+			buf.WriteString(strconv.Quote(s.Text))
+		} else {
+			buf.WriteString(s.Text)
+		}
+		buf.WriteByte('\n')
+	}
+
+	out := buf.Bytes()
+	if len(out) > 0 {
+		out = out[:len(out)-1]
+	}
+
+	return out, nil
+}
@@ -0,0 +1,78 @@
+package main
+
+import "testing"
+
+func TestGettextParseSingularAndPlural(t *testing.T) {
+	data := []byte(
+		"msgid \"apple\"\n" +
+			"msgstr \"manzana\"\n" +
+			"\n" +
+			"msgid \"%d apples\"\n" +
+			"msgid_plural \"%d apples\"\n" +
+			"msgstr[0] \"%d manzana\"\n" +
+			"msgstr[1] \"%d manzanas\"\n",
+	)
+
+	segs, err := gettextFormat{}.Parse(data)
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+
+	var translatable []segment
+	for _, s := range segs {
+		if s.Translatable {
+			translatable = append(translatable, s)
+		}
+	}
+
+	want := []struct {
+		text string
+		tag  string
+	}{
+		{"manzana", "msgstr"},
+		{"%d manzana", "msgstr[0]"},
+		{"%d manzanas", "msgstr[1]"},
+	}
+
+	if len(translatable) != len(want) {
+		t.Fatalf("got %d translatable segments, want %d: %+v", len(translatable), len(want), translatable)
+	}
+	for i, w := range want {
+		if translatable[i].Text != w.text || translatable[i].tag != w.tag {
+			t.Errorf("segment %d = %+v, want text=%q tag=%q", i, translatable[i], w.text, w.tag)
+		}
+	}
+}
+
+func TestGettextRenderRoundTripsPluralTags(t *testing.T) {
+	data := []byte(
+		"msgid \"%d apples\"\n" +
+			"msgid_plural \"%d apples\"\n" +
+			"msgstr[0] \"%d manzana\"\n" +
+			"msgstr[1] \"%d manzanas\"\n",
+	)
+
+	segs, err := gettextFormat{}.Parse(data)
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+
+	out, err := gettextFormat{}.Render(segs)
+	if err != nil {
+		t.Fatalf("Render: %v", err)
+	}
+
+	if string(out) != string(data) {
+		t.Errorf("Render round trip mismatch:\ngot:  %q\nwant: %q", out, data)
+	}
+}
+
+func TestGettextEmptyMsgstrIsNotTranslatable(t *testing.T) {
+	segs, err := gettextFormat{}.Parse([]byte(`msgstr ""`))
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	if len(segs) != 1 || segs[0].Translatable {
+		t.Fatalf("expected a single non-translatable segment, got %+v", segs)
+	}
+}
@@ -2,54 +2,57 @@ package main
 
 import (
 	"bufio"
-	"bytes"
-	"encoding/json"
-	"errors"
+	"context"
 	"flag"
 	"fmt"
-	"io"
 	"log"
-	"net/http"
 	"os"
-	"path/filepath"
+	"os/signal"
 	"strings"
+	"time"
 
 	"github.com/atotto/clipboard"
-	"gopkg.in/yaml.v2"
 )
 
-type config struct {
-	APIToken string `yaml:"api_token"`
-}
-
-type message struct {
-	Role    string `json:"role"`
-	Content string `json:"content"`
-}
-
-type chatRequest struct {
-	Model    string    `json:"model"`
-	Messages []message `json:"messages"`
-}
+func main() {
+	if len(os.Args) > 1 && os.Args[1] == "serve" {
+		cfg, err := loadConfigFile()
+		if err != nil {
+			log.Printf("No usable config file: %v\n", err)
+		}
+		if err := runServeCommand(os.Args[2:], cfg); err != nil {
+			log.Fatalf("Server failed: %v", err)
+		}
+		return
+	}
 
-type chatResponse struct {
-	Choices []struct {
-		Message message `json:"message"`
-	} `json:"choices"`
-	Error struct {
-		Message string `json:"message"`
-		Type    string `json:"type"`
-	} `json:"error"`
-}
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
 
-func main() {
 	fromLang := flag.String("f", "en", "Source language")
 	flag.StringVar(fromLang, "from", "en", "Source language")
 	toLang := flag.String("t", "en", "Target language")
 	flag.StringVar(toLang, "to", "en", "Target language")
-	apiToken := flag.String("a", "", "OpenAI API token")
+	apiToken := flag.String("a", "", "API token for the selected provider")
+	provider := flag.String("p", "", "Translation provider (openai, azure, deepl, or a LocalAI/Ollama-compatible name)")
+	flag.StringVar(provider, "provider", "", "Translation provider (openai, azure, deepl, or a LocalAI/Ollama-compatible name)")
+	baseURL := flag.String("base-url", "", "Base URL for the provider's API (required for compatible/Azure endpoints; optional for DeepL)")
+	model := flag.String("model", "", "Model or deployment name to request")
 	copyOutput := flag.Bool("c", true, "Copy output to clipboard")
 	flag.BoolVar(copyOutput, "copy", true, "Copy output to clipboard")
+	stream := flag.Bool("s", false, "Stream the translation as it is generated")
+	flag.BoolVar(stream, "stream", false, "Stream the translation as it is generated")
+	interactive := flag.Bool("i", false, "Start an interactive REPL session")
+	flag.BoolVar(interactive, "interactive", false, "Start an interactive REPL session")
+	file := flag.String("F", "", "Translate a .srt/.vtt/.md/.po/.json/.yaml file in place")
+	flag.StringVar(file, "file", "", "Translate a .srt/.vtt/.md/.po/.json/.yaml file in place")
+	out := flag.String("o", "", "Write the translated file to this path instead of in place")
+	flag.StringVar(out, "out", "", "Write the translated file to this path instead of in place")
+	glob := flag.String("glob", "", "Translate every file matching this pattern, recursively")
+	chunkTokens := flag.Int("chunk-tokens", defaultChunkTokenBudget, "Approximate token budget per batch translation request")
+	timeout := flag.Duration("timeout", 0, "HTTP request timeout (default 30s)")
+	maxRetries := flag.Int("max-retries", -1, "Maximum retry attempts for failed requests (default 5)")
+	retryBaseDelay := flag.Duration("retry-base-delay", 0, "Base delay between retries, doubling each attempt (default 500ms)")
 	verbose := flag.Bool("verbose", false, "Enable verbose mode")
 	version := flag.Bool("version", false, "Show version")
 	flag.BoolVar(version, "v", false, "Show version")
@@ -89,147 +92,124 @@ func main() {
 		}
 	}
 
-	var token string
-	if *apiToken == "" {
-		var err error
-		token, err = loadTokenFromConfig()
-		if err != nil {
-			log.Fatalf("Failed to load API token: %v", err)
-		}
-	} else {
-		token = *apiToken
+	cfg, err := loadConfigFile()
+	if err != nil && *verbose {
+		log.Printf("No usable config file: %v\n", err)
 	}
 
-	if *verbose {
-		log.Printf("OpenAI Token: %s\n", token)
-	}
+	retryOpts := resolveRetryOptions(cfg, *timeout, *maxRetries, *retryBaseDelay)
 
-	translatedText, err := translate(token, *fromLang, *toLang, textToTranslate, *verbose)
+	translator, err := newTranslator(*provider, cfg, *apiToken, *baseURL, *model, *verbose, retryOpts)
 	if err != nil {
-		log.Fatalf("Translation failed: %v", err)
+		log.Fatalf("Failed to initialize translator: %v", err)
 	}
 
-	fmt.Println(translatedText)
-
-	if *copyOutput {
-		if err := clipboard.WriteAll(translatedText); err != nil {
-			log.Fatalf("Failed to copy translation to clipboard: %v", err)
+	if *interactive {
+		if err := runREPL(ctx, translator, *fromLang, *toLang, *stream, *copyOutput, *verbose); err != nil {
+			log.Fatalf("Interactive session failed: %v", err)
 		}
+		return
 	}
-}
 
-func loadTokenFromConfig() (string, error) {
-	home, err := os.UserHomeDir()
-	if err != nil {
-		return "", fmt.Errorf("failed to retrieve home directory: %w", err)
+	if *file != "" || *glob != "" {
+		if err := runBatchTranslation(ctx, translator, *fromLang, *toLang, *file, *out, *glob, *chunkTokens, *verbose); err != nil {
+			log.Fatalf("Batch translation failed: %v", err)
+		}
+		return
 	}
 
-	configPath := filepath.Join(home, ".config", "openapi", "secret.yml")
-	data, err := os.ReadFile(configPath)
-	if err != nil {
-		return "", fmt.Errorf("failed to read config file: %w", err)
+	var translatedText string
+	if *stream {
+		translatedText, err = translator.TranslateStream(ctx, *fromLang, *toLang, textToTranslate, os.Stdout)
+		fmt.Println()
+	} else {
+		translatedText, err = translator.Translate(ctx, *fromLang, *toLang, textToTranslate)
+		fmt.Println(translatedText)
 	}
-
-	var config config
-	err = yaml.Unmarshal(data, &config)
 	if err != nil {
-		return "", fmt.Errorf("failed to parse config file: %w", err)
+		log.Fatalf("Translation failed: %v", err)
 	}
 
-	if config.APIToken == "" {
-		return "", fmt.Errorf("API token not found in config file")
+	if *copyOutput {
+		if err := clipboard.WriteAll(translatedText); err != nil {
+			log.Fatalf("Failed to copy translation to clipboard: %v", err)
+		}
 	}
-
-	return config.APIToken, nil
 }
 
-func translate(apiKey, fromLang, toLang, text string, verbose bool) (string, error) {
-	url := "https://api.openai.com/v1/chat/completions"
-
-	messages := []message{
-		{
-			Role:    "system",
-			Content: "You are a translator that only gives the translated text",
-		},
-		{
-			Role:    "user",
-			Content: fmt.Sprintf("Translate this from %s to %s: %s", fromLang, toLang, text),
-		},
-	}
-
-	chatRequest := chatRequest{
-		Model:    "gpt-4o-mini",
-		Messages: messages,
-	}
-
-	jsonPayload, err := json.Marshal(chatRequest)
-	if err != nil {
-		return "", fmt.Errorf("failed to marshal JSON payload: %w", err)
-	}
-
-	if verbose {
-		log.Printf("Request Payload: %s\n", string(jsonPayload))
-	}
-
-	req, err := http.NewRequest("POST", url, bytes.NewBuffer(jsonPayload))
-	if err != nil {
-		return "", fmt.Errorf("failed to create new HTTP request: %w", err)
-	}
-
-	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("Authorization", "Bearer "+apiKey)
-
-	client := &http.Client{}
-	resp, err := client.Do(req)
-	if err != nil {
-		return "", fmt.Errorf("failed to execute HTTP request: %w", err)
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK {
-		body, _ := io.ReadAll(resp.Body)
-		if verbose {
-			log.Printf("Response Status: %s\n", resp.Status)
-			log.Printf("Response Headers: %v\n", resp.Header)
-			log.Printf("Response Body: %s\n", string(body))
+// resolveRetryOptions merges CLI flags (sentinel zero/negative values
+// mean "not set") with config file defaults, then hardcoded fallbacks.
+func resolveRetryOptions(cfg config, timeout time.Duration, maxRetries int, baseDelay time.Duration) retryOptions {
+	if timeout <= 0 {
+		if cfg.TimeoutSeconds > 0 {
+			timeout = time.Duration(cfg.TimeoutSeconds) * time.Second
+		} else {
+			timeout = 30 * time.Second
 		}
-		return "", fmt.Errorf("unexpected status code from OpenAI API: %d", resp.StatusCode)
 	}
 
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return "", fmt.Errorf("failed to read response body: %w", err)
-	}
-
-	var chatResponse chatResponse
-	if err := json.Unmarshal(body, &chatResponse); err != nil {
-		return "", fmt.Errorf("failed to unmarshal response JSON: %w", err)
-	}
-
-	if chatResponse.Error.Message != "" {
-		if verbose {
-			log.Printf("OpenAI API Error: %s (Type: %s)\n", chatResponse.Error.Message, chatResponse.Error.Type)
+	if maxRetries < 0 {
+		if cfg.MaxRetries > 0 {
+			maxRetries = cfg.MaxRetries
+		} else {
+			maxRetries = 5
 		}
-		return "", errors.New(chatResponse.Error.Message)
 	}
 
-	if len(chatResponse.Choices) > 0 {
-		return strings.TrimSpace(chatResponse.Choices[0].Message.Content), nil
+	if baseDelay <= 0 {
+		if cfg.RetryBaseDelayMS > 0 {
+			baseDelay = time.Duration(cfg.RetryBaseDelayMS) * time.Millisecond
+		} else {
+			baseDelay = 500 * time.Millisecond
+		}
 	}
 
-	return "", errors.New("no translation found in response")
+	return retryOptions{Timeout: timeout, MaxRetries: maxRetries, BaseDelay: baseDelay}
 }
 
 func usage() {
 	msg := `
 Usage:
   translate-cli [options] <text>
+  translate-cli serve [options]
+
+Serve Subcommand:
+  Runs an HTTP server exposing the configured translator as an API:
+    POST /v1/translations      {from, to, text, stream} -> {translation}
+    POST /v1/chat/completions  OpenAI-compatible proxy (any SDK can point
+                                at this server; source/target languages
+                                come from X-From-Lang/X-To-Lang headers)
+    GET  /healthz               Liveness check
+
+  Options:
+    -host <addr>             Host to bind (default: 0.0.0.0)
+    -port <port>             Port to bind (default: 8080)
+    -upload-limit-mb <n>     Maximum request body size in MB (default: 10)
+    -cors                    Enable permissive CORS headers
+    -p, -provider, -base-url, -model, -a, -verbose,
+    -timeout, -max-retries, -retry-base-delay        Same as above
+
+  Set serve.bearer_token in the config file to require
+  "Authorization: Bearer <token>" on every request.
 
 Options:
   -f, -from <language>      Source language (default: en)
   -t, -to   <language>      Target language (default: en)
-  -a        <api token>     OpenAI API token (default: ~/.config/openai/secret.yml)
+  -a        <api token>     API token for the selected provider
+  -p, -provider <name>      Translation provider: openai (default), azure, deepl,
+                             or any OpenAI-compatible name (LocalAI, Ollama, vLLM)
+  -base-url <url>           Base URL for the provider's API
+  -model    <name>          Model or deployment name to request
   -c, -copy                 Copy output to clipboard (default: true)
+  -s, -stream               Stream the translation as it is generated (default: false)
+  -i, -interactive          Start an interactive REPL session (default: false)
+  -F, -file <path>          Translate a .srt/.vtt/.md/.po/.json/.yaml file in place
+  -o, -out  <path>          Write the translated file here instead of in place
+  -glob     <pattern>       Translate every file matching pattern, recursively
+  -chunk-tokens <n>         Approximate token budget per batch request (default: 2000)
+  -timeout <duration>       HTTP request timeout (default: 30s)
+  -max-retries <n>          Maximum retry attempts for failed requests (default: 5)
+  -retry-base-delay <dur>   Base delay between retries, doubling each attempt (default: 500ms)
   -verbose                  Enable verbose mode (default: false)
   -v, -version              Show version
   -help                     Show this help message
@@ -237,14 +217,63 @@ Options:
 Examples:
   translate-cli -f en -t es "Hello, how are you?"
   echo "Hello, how are you?" | translate-cli -f en -t es
+  translate-cli -i -f en -t es
+  translate-cli -p localai -base-url http://localhost:8080 -model mistral "Hello"
+  translate-cli -p deepl -a $DEEPL_KEY -f en -t de "Hello"
+  translate-cli -F subtitles.srt -f en -t pt
+  translate-cli -glob "*.md" -f en -t fr
+
+Batch File Mode:
+  -F/--file translates one .srt, .vtt, .md, .po, .json, or .yaml file,
+  preserving its structure (timestamps, code fences, msgid keys, object
+  keys) and translating only the human-readable text. -glob walks the
+  current directory recursively, translating every matching file in
+  place. Segments are grouped into requests up to -chunk-tokens; pass a
+  smaller budget for APIs with tight context windows.
+
+Interactive Mode:
+  In -i/--interactive mode, each line you type is translated using the
+  prior turns as context. The following commands are also available:
+    :from <lang>   Change the source language
+    :to <lang>     Change the target language
+    :reset         Clear the conversation history
+    :save <file>   Save the transcript as JSON or YAML (by file extension)
+    :copy          Copy the last translation to the clipboard
+    :quit          Exit the session
+
+  Set enable_history: true in the config file to persist a rolling
+  transcript to ~/.config/openapi/history.jsonl across sessions.
+
+Reliability:
+  Requests are retried with exponential backoff on HTTP 429 (honoring
+  Retry-After), 5xx responses, and transient network errors. Press
+  Ctrl+C at any point to cancel an in-flight translation, stream, or
+  batch run.
+
+Config File:
+  The config file is a YAML file located at ~/.config/openapi/secret.yml:
+
+    api_token: YOUR_DEFAULT_API_TOKEN
+    default_provider: openai
+    enable_history: false
+    timeout_seconds: 30
+    max_retries: 5
+    retry_base_delay_ms: 500
+    providers:
+      openai:
+        api_token: YOUR_OPENAI_TOKEN
+      localai:
+        base_url: http://localhost:8080
+        model: mistral
+      azure:
+        api_token: YOUR_AZURE_TOKEN
+        base_url: https://your-resource.openai.azure.com
+        deployment: your-deployment
+        api_version: 2024-02-01
+      deepl:
+        api_token: YOUR_DEEPL_TOKEN
 
-OpenAI API Token:
   You can obtain an OpenAI API token from https://platform.openai.com/api-keys.
-  
-  The config file should be a YAML file with the following structure:
-    api_token: YOUR_API_TOKEN
-
-  And should be located at ~/.config/openai/secret.yml
 `
 	fmt.Fprintln(os.Stdout, msg)
 }
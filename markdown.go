@@ -0,0 +1,125 @@
+package main
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// markdownFormat translates prose lines while leaving fenced code
+// blocks untouched, and replacing inline code spans, URLs, and link
+// targets within a prose line with placeholders before translation so
+// they survive round-tripping unchanged.
+type markdownFormat struct{}
+
+var (
+	mdFenceRe      = regexp.MustCompile("^```")
+	mdInlineCodeRe = regexp.MustCompile("`[^`]*`")
+	mdURLRe        = regexp.MustCompile(`\bhttps?://\S+`)
+	mdLinkTargetRe = regexp.MustCompile(`\]\(([^)]+)\)`)
+	mdSpanRe       = regexp.MustCompile(mdInlineCodeRe.String() + "|" + mdURLRe.String() + "|" + mdLinkTargetRe.String())
+	mdPlaceholdRe  = regexp.MustCompile(`⟦(\d+)⟧`)
+)
+
+// mdPlaceholder formats a span index as a token unlikely to appear in
+// ordinary prose or to be touched by a translation model.
+func mdPlaceholder(i int) string {
+	return "⟦" + strconv.Itoa(i) + "⟧"
+}
+
+func (markdownFormat) Parse(data []byte) ([]segment, error) {
+	lines := strings.Split(string(data), "\n")
+	segs := make([]segment, 0, len(lines))
+	inFence := false
+
+	for _, line := range lines {
+		trimmed := strings.TrimSpace(line)
+
+		if mdFenceRe.MatchString(trimmed) {
+			inFence = !inFence
+			segs = append(segs, segment{Text: line, Translatable: false})
+			continue
+		}
+
+		if inFence || trimmed == "" {
+			segs = append(segs, segment{Text: line, Translatable: false})
+			continue
+		}
+
+		masked, spans := maskInlineSpans(line)
+		if strings.TrimSpace(mdPlaceholdRe.ReplaceAllString(masked, "")) == "" {
+			// Nothing but code/links/whitespace: leave the original line
+			// untouched rather than sending empty prose to the model.
+			segs = append(segs, segment{Text: line, Translatable: false})
+			continue
+		}
+
+		segs = append(segs, segment{Text: masked, Translatable: true, spans: spans, original: line})
+	}
+
+	return segs, nil
+}
+
+// maskInlineSpans replaces inline code spans, bare URLs, and markdown
+// link targets within line with placeholders, returning the masked line
+// and the original text of each placeholder in order.
+func maskInlineSpans(line string) (string, []string) {
+	var spans []string
+	masked := mdSpanRe.ReplaceAllStringFunc(line, func(match string) string {
+		spans = append(spans, match)
+		return mdPlaceholder(len(spans) - 1)
+	})
+	return masked, spans
+}
+
+// restoreInlineSpans substitutes each placeholder in line with its
+// original span text, leaving unrecognized placeholders as-is.
+func restoreInlineSpans(line string, spans []string) string {
+	return mdPlaceholdRe.ReplaceAllStringFunc(line, func(match string) string {
+		idx, err := strconv.Atoi(mdPlaceholdRe.FindStringSubmatch(match)[1])
+		if err != nil || idx < 0 || idx >= len(spans) {
+			return match
+		}
+		return spans[idx]
+	})
+}
+
+// placeholdersIntact reports whether line contains exactly one
+// occurrence of each placeholder index in [0, want), the way a model is
+// expected to reproduce them. A model is not guaranteed to preserve
+// inline placeholders any more reliably than the batch segment
+// delimiter, so Render falls back to the untranslated line whenever
+// this doesn't hold, rather than risk substituting spans into the wrong
+// positions or dropping them.
+func placeholdersIntact(line string, want int) bool {
+	matches := mdPlaceholdRe.FindAllStringSubmatch(line, -1)
+	if len(matches) != want {
+		return false
+	}
+
+	seen := make(map[int]bool, want)
+	for _, m := range matches {
+		idx, err := strconv.Atoi(m[1])
+		if err != nil || idx < 0 || idx >= want || seen[idx] {
+			return false
+		}
+		seen[idx] = true
+	}
+	return true
+}
+
+func (markdownFormat) Render(segs []segment) ([]byte, error) {
+	lines := make([]string, len(segs))
+	for i, s := range segs {
+		if s.Translatable && len(s.spans) > 0 {
+			if !placeholdersIntact(s.Text, len(s.spans)) {
+				lines[i] = s.original
+				continue
+			}
+			lines[i] = restoreInlineSpans(s.Text, s.spans)
+		} else {
+			lines[i] = s.Text
+		}
+	}
+	return []byte(strings.Join(lines, "\n")), nil
+}
@@ -0,0 +1,129 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestMarkdownParseSkipsFencedCode(t *testing.T) {
+	data := []byte("intro\n```go\nfmt.Println(\"hi\")\n```\noutro")
+	segs, err := markdownFormat{}.Parse(data)
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+
+	want := []bool{true, false, false, false, true}
+	if len(segs) != len(want) {
+		t.Fatalf("got %d segments, want %d", len(segs), len(want))
+	}
+	for i, w := range want {
+		if segs[i].Translatable != w {
+			t.Errorf("segment %d (%q): Translatable = %v, want %v", i, segs[i].Text, segs[i].Translatable, w)
+		}
+	}
+}
+
+func TestMarkdownInlineCodeAndURLsSurviveRoundTrip(t *testing.T) {
+	line := "Check out `fooBar()` for details, see https://example.com for more."
+	segs, err := markdownFormat{}.Parse([]byte(line))
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	if len(segs) != 1 || !segs[0].Translatable {
+		t.Fatalf("expected one translatable segment, got %+v", segs)
+	}
+
+	// Simulate a translation pass that only touches the masked prose.
+	segs[0].Text = strings.ToUpper(segs[0].Text)
+
+	out, err := markdownFormat{}.Render(segs)
+	if err != nil {
+		t.Fatalf("Render: %v", err)
+	}
+
+	rendered := string(out)
+	if !strings.Contains(rendered, "`fooBar()`") || !strings.Contains(rendered, "https://example.com") {
+		t.Errorf("expected code span and URL preserved verbatim, got %q", rendered)
+	}
+}
+
+func TestMarkdownLinkTargetPreservedButTextTranslated(t *testing.T) {
+	line := "See [our docs](https://example.com/docs) for more."
+	segs, err := markdownFormat{}.Parse([]byte(line))
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	if len(segs) != 1 || !segs[0].Translatable {
+		t.Fatalf("expected one translatable segment, got %+v", segs)
+	}
+
+	segs[0].Text = strings.ToUpper(segs[0].Text)
+
+	out, err := markdownFormat{}.Render(segs)
+	if err != nil {
+		t.Fatalf("Render: %v", err)
+	}
+
+	rendered := string(out)
+	if !strings.Contains(rendered, "(https://example.com/docs)") {
+		t.Errorf("expected link target preserved verbatim, got %q", rendered)
+	}
+}
+
+func TestMarkdownRenderFallsBackWhenPlaceholderIsLost(t *testing.T) {
+	line := "Check out `fooBar()` for details."
+	segs, err := markdownFormat{}.Parse([]byte(line))
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	if len(segs) != 1 || !segs[0].Translatable {
+		t.Fatalf("expected one translatable segment, got %+v", segs)
+	}
+
+	// Simulate a model that mangled the placeholder instead of
+	// reproducing it verbatim.
+	segs[0].Text = strings.Replace(segs[0].Text, "⟦0⟧", "", 1)
+
+	out, err := markdownFormat{}.Render(segs)
+	if err != nil {
+		t.Fatalf("Render: %v", err)
+	}
+
+	if string(out) != line {
+		t.Errorf("expected the untranslated original line as a fallback, got %q", out)
+	}
+}
+
+func TestMarkdownRenderFallsBackWhenPlaceholderIsDuplicated(t *testing.T) {
+	line := "See `a` and `b` here."
+	segs, err := markdownFormat{}.Parse([]byte(line))
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	if len(segs) != 1 || !segs[0].Translatable {
+		t.Fatalf("expected one translatable segment, got %+v", segs)
+	}
+
+	// A model that duplicates one placeholder and drops another should
+	// also be treated as a mismatch, not silently substituted.
+	segs[0].Text = strings.Replace(segs[0].Text, "⟦1⟧", "⟦0⟧", 1)
+
+	out, err := markdownFormat{}.Render(segs)
+	if err != nil {
+		t.Fatalf("Render: %v", err)
+	}
+
+	if string(out) != line {
+		t.Errorf("expected the untranslated original line as a fallback, got %q", out)
+	}
+}
+
+func TestMarkdownLineThatIsOnlyCodeIsNotTranslatable(t *testing.T) {
+	segs, err := markdownFormat{}.Parse([]byte("`fooBar()`"))
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	if len(segs) != 1 || segs[0].Translatable {
+		t.Fatalf("expected a single non-translatable segment, got %+v", segs)
+	}
+}
@@ -0,0 +1,36 @@
+package main
+
+import (
+	"context"
+	"io"
+	"time"
+)
+
+const openAIChatURL = "https://api.openai.com/v1/chat/completions"
+
+// openAITranslator talks directly to the OpenAI chat completions API.
+type openAITranslator struct {
+	client *chatClient
+}
+
+func newOpenAITranslator(apiToken, model string, verbose bool, timeout time.Duration, maxRetries int, baseDelay time.Duration) *openAITranslator {
+	return &openAITranslator{
+		client: newChatClient(openAIChatURL, map[string]string{"Authorization": "Bearer " + apiToken}, model, verbose, timeout, maxRetries, baseDelay),
+	}
+}
+
+func (t *openAITranslator) Translate(ctx context.Context, fromLang, toLang, text string) (string, error) {
+	return t.client.translate(ctx, fromLang, toLang, text)
+}
+
+func (t *openAITranslator) TranslateStream(ctx context.Context, fromLang, toLang, text string, w io.Writer) (string, error) {
+	return t.client.translateStream(ctx, fromLang, toLang, text, w)
+}
+
+func (t *openAITranslator) ChatTranslate(ctx context.Context, history []message) (string, error) {
+	return t.client.chatOnce(ctx, history)
+}
+
+func (t *openAITranslator) ChatTranslateStream(ctx context.Context, history []message, w io.Writer) (string, error) {
+	return t.client.chatStream(ctx, history, w)
+}
@@ -0,0 +1,225 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/atotto/clipboard"
+	"gopkg.in/yaml.v2"
+)
+
+// runREPL starts an interactive translation session, reading lines from
+// stdin and keeping prior turns as context so pronoun and topic
+// disambiguation carries across sentences. If the translator does not
+// support carrying history (e.g. DeepL), each line is translated in
+// isolation instead. ctx is passed to every translation request, so
+// canceling it (e.g. on SIGINT) aborts an in-flight call; it does not
+// interrupt a blocked stdin read, so the next request must complete or
+// be canceled before the session fully exits.
+func runREPL(ctx context.Context, translator Translator, fromLang, toLang string, stream, copyOutput, verbose bool) error {
+	historyTranslator, hasHistory := translator.(chatHistoryTranslator)
+
+	history := []message{{Role: "system", Content: translatorSystemPrompt}}
+	lastTranslation := ""
+
+	historyFile, err := openHistoryFile()
+	if err != nil && verbose {
+		fmt.Fprintf(os.Stderr, "Warning: history disabled: %v\n", err)
+	}
+	if historyFile != nil {
+		defer historyFile.Close()
+	}
+
+	fmt.Printf("Interactive mode: %s -> %s. Type :quit to exit, :help for commands.\n", fromLang, toLang)
+
+	scanner := bufio.NewScanner(os.Stdin)
+	for {
+		fmt.Printf("[%s->%s]> ", fromLang, toLang)
+		if !scanner.Scan() {
+			fmt.Println()
+			return scanner.Err()
+		}
+
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+
+		if strings.HasPrefix(line, ":") {
+			done, err := handleREPLCommand(line, &fromLang, &toLang, &history, lastTranslation, copyOutput)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			}
+			if done {
+				return nil
+			}
+			continue
+		}
+
+		var translated string
+		if hasHistory {
+			history = append(history, message{Role: "user", Content: fmt.Sprintf("Translate this from %s to %s: %s", fromLang, toLang, line)})
+
+			if stream {
+				translated, err = historyTranslator.ChatTranslateStream(ctx, history, os.Stdout)
+				fmt.Println()
+			} else {
+				translated, err = historyTranslator.ChatTranslate(ctx, history)
+				fmt.Println(translated)
+			}
+			if err != nil {
+				history = history[:len(history)-1]
+			} else {
+				history = append(history, message{Role: "assistant", Content: translated})
+			}
+		} else {
+			if stream {
+				translated, err = translator.TranslateStream(ctx, fromLang, toLang, line, os.Stdout)
+				fmt.Println()
+			} else {
+				translated, err = translator.Translate(ctx, fromLang, toLang, line)
+				fmt.Println(translated)
+			}
+		}
+		if err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			fmt.Fprintf(os.Stderr, "Translation failed: %v\n", err)
+			continue
+		}
+
+		lastTranslation = translated
+
+		appendToHistoryFile(historyFile, fromLang, toLang, line, translated)
+	}
+}
+
+// handleREPLCommand processes a leading-colon REPL command. It reports
+// whether the session should end.
+func handleREPLCommand(line string, fromLang, toLang *string, history *[]message, lastTranslation string, copyOutput bool) (bool, error) {
+	fields := strings.Fields(line)
+	cmd := fields[0]
+	arg := ""
+	if len(fields) > 1 {
+		arg = strings.Join(fields[1:], " ")
+	}
+
+	switch cmd {
+	case ":quit", ":exit":
+		return true, nil
+	case ":help":
+		fmt.Println(`Commands: :from <lang>  :to <lang>  :reset  :save <file>  :copy  :quit`)
+		return false, nil
+	case ":from":
+		if arg == "" {
+			return false, fmt.Errorf("usage: :from <lang>")
+		}
+		*fromLang = arg
+		fmt.Printf("Source language set to %s\n", arg)
+		return false, nil
+	case ":to":
+		if arg == "" {
+			return false, fmt.Errorf("usage: :to <lang>")
+		}
+		*toLang = arg
+		fmt.Printf("Target language set to %s\n", arg)
+		return false, nil
+	case ":reset":
+		*history = []message{{Role: "system", Content: translatorSystemPrompt}}
+		fmt.Println("Conversation history cleared.")
+		return false, nil
+	case ":save":
+		if arg == "" {
+			return false, fmt.Errorf("usage: :save <file>")
+		}
+		return false, saveTranscript(arg, *history)
+	case ":copy":
+		if lastTranslation == "" {
+			return false, fmt.Errorf("no translation to copy yet")
+		}
+		if err := clipboard.WriteAll(lastTranslation); err != nil {
+			return false, fmt.Errorf("failed to copy to clipboard: %w", err)
+		}
+		fmt.Println("Copied last translation to clipboard.")
+		return false, nil
+	default:
+		return false, fmt.Errorf("unknown command: %s", cmd)
+	}
+}
+
+// saveTranscript dumps the conversation history to path as JSON or YAML,
+// chosen by the file's extension (defaulting to JSON).
+func saveTranscript(path string, history []message) error {
+	var data []byte
+	var err error
+
+	if strings.HasSuffix(path, ".yaml") || strings.HasSuffix(path, ".yml") {
+		data, err = yaml.Marshal(history)
+	} else {
+		data, err = json.MarshalIndent(history, "", "  ")
+	}
+	if err != nil {
+		return fmt.Errorf("failed to encode transcript: %w", err)
+	}
+
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		return fmt.Errorf("failed to write transcript: %w", err)
+	}
+
+	fmt.Printf("Transcript saved to %s\n", path)
+	return nil
+}
+
+type historyEntry struct {
+	From        string `json:"from"`
+	To          string `json:"to"`
+	Input       string `json:"input"`
+	Translation string `json:"translation"`
+}
+
+// openHistoryFile opens the rolling history log for appending, gated on
+// the enable_history config key. A nil file with a nil error means
+// history is simply disabled.
+func openHistoryFile() (*os.File, error) {
+	cfg, err := loadConfigFile()
+	if err != nil || !cfg.EnableHistory {
+		return nil, nil
+	}
+
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve home directory: %w", err)
+	}
+
+	dir := filepath.Join(home, ".config", "openapi")
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create config directory: %w", err)
+	}
+
+	path := filepath.Join(dir, "history.jsonl")
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open history file: %w", err)
+	}
+
+	return file, nil
+}
+
+func appendToHistoryFile(file *os.File, from, to, input, translation string) {
+	if file == nil {
+		return
+	}
+
+	entry, err := json.Marshal(historyEntry{From: from, To: to, Input: input, Translation: translation})
+	if err != nil {
+		return
+	}
+
+	fmt.Fprintf(file, "%s\n", entry)
+}
@@ -0,0 +1,194 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+	"testing"
+)
+
+func TestHandleREPLCommand(t *testing.T) {
+	t.Run("from requires an argument", func(t *testing.T) {
+		from, to := "en", "fr"
+		history := []message{{Role: "system", Content: translatorSystemPrompt}}
+		if _, err := handleREPLCommand(":from", &from, &to, &history, "", false); err == nil {
+			t.Fatal("expected an error for :from with no argument")
+		}
+	})
+
+	t.Run("from sets the source language", func(t *testing.T) {
+		from, to := "en", "fr"
+		history := []message{{Role: "system", Content: translatorSystemPrompt}}
+		if _, err := handleREPLCommand(":from pt", &from, &to, &history, "", false); err != nil {
+			t.Fatalf("handleREPLCommand: %v", err)
+		}
+		if from != "pt" {
+			t.Errorf("from = %q, want %q", from, "pt")
+		}
+	})
+
+	t.Run("to sets the target language", func(t *testing.T) {
+		from, to := "en", "fr"
+		history := []message{{Role: "system", Content: translatorSystemPrompt}}
+		if _, err := handleREPLCommand(":to de", &from, &to, &history, "", false); err != nil {
+			t.Fatalf("handleREPLCommand: %v", err)
+		}
+		if to != "de" {
+			t.Errorf("to = %q, want %q", to, "de")
+		}
+	})
+
+	t.Run("reset clears history down to the system prompt", func(t *testing.T) {
+		from, to := "en", "fr"
+		history := []message{
+			{Role: "system", Content: translatorSystemPrompt},
+			{Role: "user", Content: "hi"},
+			{Role: "assistant", Content: "salut"},
+		}
+		if _, err := handleREPLCommand(":reset", &from, &to, &history, "", false); err != nil {
+			t.Fatalf("handleREPLCommand: %v", err)
+		}
+		if len(history) != 1 || history[0].Role != "system" {
+			t.Errorf("history after :reset = %+v, want just the system prompt", history)
+		}
+	})
+
+	t.Run("save writes the transcript to the given file", func(t *testing.T) {
+		from, to := "en", "fr"
+		history := []message{{Role: "system", Content: translatorSystemPrompt}, {Role: "user", Content: "hi"}}
+		path := t.TempDir() + "/transcript.json"
+		if _, err := handleREPLCommand(":save "+path, &from, &to, &history, "", false); err != nil {
+			t.Fatalf("handleREPLCommand: %v", err)
+		}
+		if _, err := os.Stat(path); err != nil {
+			t.Errorf("expected %s to be written: %v", path, err)
+		}
+	})
+
+	t.Run("save requires an argument", func(t *testing.T) {
+		from, to := "en", "fr"
+		history := []message{{Role: "system", Content: translatorSystemPrompt}}
+		if _, err := handleREPLCommand(":save", &from, &to, &history, "", false); err == nil {
+			t.Fatal("expected an error for :save with no argument")
+		}
+	})
+
+	t.Run("copy with nothing translated yet errors", func(t *testing.T) {
+		from, to := "en", "fr"
+		history := []message{{Role: "system", Content: translatorSystemPrompt}}
+		if _, err := handleREPLCommand(":copy", &from, &to, &history, "", false); err == nil {
+			t.Fatal("expected an error for :copy with no prior translation")
+		}
+	})
+
+	t.Run("quit and exit end the session", func(t *testing.T) {
+		for _, cmd := range []string{":quit", ":exit"} {
+			from, to := "en", "fr"
+			history := []message{{Role: "system", Content: translatorSystemPrompt}}
+			done, err := handleREPLCommand(cmd, &from, &to, &history, "", false)
+			if err != nil {
+				t.Fatalf("%s: %v", cmd, err)
+			}
+			if !done {
+				t.Errorf("%s: expected the session to end", cmd)
+			}
+		}
+	})
+
+	t.Run("help does not end the session", func(t *testing.T) {
+		from, to := "en", "fr"
+		history := []message{{Role: "system", Content: translatorSystemPrompt}}
+		done, err := handleREPLCommand(":help", &from, &to, &history, "", false)
+		if err != nil {
+			t.Fatalf("handleREPLCommand: %v", err)
+		}
+		if done {
+			t.Error(":help should not end the session")
+		}
+	})
+
+	t.Run("unknown command errors without ending the session", func(t *testing.T) {
+		from, to := "en", "fr"
+		history := []message{{Role: "system", Content: translatorSystemPrompt}}
+		done, err := handleREPLCommand(":bogus", &from, &to, &history, "", false)
+		if err == nil {
+			t.Fatal("expected an error for an unknown command")
+		}
+		if done {
+			t.Error("an unknown command should not end the session")
+		}
+	})
+}
+
+// scriptedChatTranslator fails the ChatTranslate call numbered failCall
+// (counting from 1) and succeeds on every other call, recording the history it was given each time so
+// tests can verify runREPL's rollback behavior on failure.
+type scriptedChatTranslator struct {
+	calls    int
+	seen     [][]message
+	failCall int
+}
+
+func (s *scriptedChatTranslator) Translate(ctx context.Context, fromLang, toLang, text string) (string, error) {
+	return text, nil
+}
+
+func (s *scriptedChatTranslator) TranslateStream(ctx context.Context, fromLang, toLang, text string, w io.Writer) (string, error) {
+	return text, nil
+}
+
+func (s *scriptedChatTranslator) ChatTranslate(ctx context.Context, history []message) (string, error) {
+	s.calls++
+	recorded := make([]message, len(history))
+	copy(recorded, history)
+	s.seen = append(s.seen, recorded)
+
+	if s.calls == s.failCall {
+		return "", errors.New("boom")
+	}
+	return "ok", nil
+}
+
+func (s *scriptedChatTranslator) ChatTranslateStream(ctx context.Context, history []message, w io.Writer) (string, error) {
+	return s.ChatTranslate(ctx, history)
+}
+
+func TestRunREPLRollsBackHistoryOnTranslationFailure(t *testing.T) {
+	origStdin := os.Stdin
+	defer func() { os.Stdin = origStdin }()
+
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatalf("os.Pipe: %v", err)
+	}
+	os.Stdin = r
+
+	go func() {
+		io.WriteString(w, "hello\nworld\n:quit\n")
+		w.Close()
+	}()
+
+	translator := &scriptedChatTranslator{failCall: 1}
+	if err := runREPL(context.Background(), translator, "en", "fr", false, false, false); err != nil {
+		t.Fatalf("runREPL: %v", err)
+	}
+
+	if translator.calls != 2 {
+		t.Fatalf("expected 2 ChatTranslate calls, got %d", translator.calls)
+	}
+
+	// The failed "hello" turn should have been rolled back, so the
+	// second call's history carries only the system prompt plus
+	// "world" — no leftover user message from the failed turn.
+	second := translator.seen[1]
+	if len(second) != 2 {
+		t.Fatalf("second call history = %+v, want 2 messages (system + world)", second)
+	}
+	if second[0].Role != "system" {
+		t.Errorf("second call history[0].Role = %q, want %q", second[0].Role, "system")
+	}
+	if second[1].Content != "Translate this from en to fr: world" {
+		t.Errorf("second call history[1].Content = %q", second[1].Content)
+	}
+}
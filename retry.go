@@ -0,0 +1,91 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// retryConfig controls doWithRetry's backoff behavior.
+type retryConfig struct {
+	MaxRetries int
+	BaseDelay  time.Duration
+	Verbose    bool
+}
+
+// doWithRetry issues an HTTP request built fresh by newReq on every
+// attempt (an *http.Request's body can only be read once, so it cannot
+// be reused across retries), retrying on 5xx responses, HTTP 429 (honoring
+// a Retry-After header), and transient network errors. Attempts are
+// spaced by an exponential backoff with jitter, capped at
+// cfg.MaxRetries retries beyond the first. It gives up early if ctx is
+// canceled.
+func doWithRetry(ctx context.Context, client *http.Client, cfg retryConfig, newReq func(context.Context) (*http.Request, error)) (*http.Response, error) {
+	var lastErr error
+	var retryAfter time.Duration
+
+	for attempt := 0; ; attempt++ {
+		req, err := newReq(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		resp, err := client.Do(req)
+		if err == nil && resp.StatusCode < http.StatusInternalServerError && resp.StatusCode != http.StatusTooManyRequests {
+			return resp, nil
+		}
+
+		if err != nil {
+			lastErr = err
+			retryAfter = 0
+		} else {
+			lastErr = fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+			retryAfter = parseRetryAfter(resp.Header.Get("Retry-After"))
+			resp.Body.Close()
+		}
+
+		if attempt >= cfg.MaxRetries {
+			return nil, lastErr
+		}
+
+		delay := retryAfter
+		if delay == 0 {
+			delay = backoffDelay(cfg.BaseDelay, attempt)
+		}
+
+		if cfg.Verbose {
+			log.Printf("Request failed (attempt %d/%d), retrying in %s: %v\n", attempt+1, cfg.MaxRetries+1, delay, lastErr)
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(delay):
+		}
+	}
+}
+
+// backoffDelay returns base * 2^attempt plus up to 50% jitter.
+func backoffDelay(base time.Duration, attempt int) time.Duration {
+	delay := base << attempt
+	jitter := time.Duration(rand.Int63n(int64(delay)/2 + 1))
+	return delay + jitter
+}
+
+// parseRetryAfter parses an HTTP Retry-After header given in seconds.
+// It returns 0 if the header is absent or not a plain integer (the
+// HTTP-date form is not supported).
+func parseRetryAfter(header string) time.Duration {
+	if header == "" {
+		return 0
+	}
+	seconds, err := strconv.Atoi(header)
+	if err != nil || seconds < 0 {
+		return 0
+	}
+	return time.Duration(seconds) * time.Second
+}
@@ -0,0 +1,168 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestBackoffDelayDoublesAndAddsJitter(t *testing.T) {
+	base := 10 * time.Millisecond
+
+	for attempt := 0; attempt < 4; attempt++ {
+		min := base << attempt
+		max := min + min/2
+		for i := 0; i < 20; i++ {
+			d := backoffDelay(base, attempt)
+			if d < min || d > max {
+				t.Fatalf("attempt %d: backoffDelay = %s, want in [%s, %s]", attempt, d, min, max)
+			}
+		}
+	}
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	cases := []struct {
+		header string
+		want   time.Duration
+	}{
+		{"", 0},
+		{"5", 5 * time.Second},
+		{"0", 0},
+		{"-1", 0},
+		{"not-a-number", 0},
+	}
+
+	for _, c := range cases {
+		if got := parseRetryAfter(c.header); got != c.want {
+			t.Errorf("parseRetryAfter(%q) = %s, want %s", c.header, got, c.want)
+		}
+	}
+}
+
+func TestDoWithRetryRetriesOn500ThenSucceeds(t *testing.T) {
+	var attempts int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		if attempts < 3 {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+
+	cfg := retryConfig{MaxRetries: 5, BaseDelay: time.Millisecond}
+	resp, err := doWithRetry(context.Background(), srv.Client(), cfg, func(ctx context.Context) (*http.Request, error) {
+		return http.NewRequestWithContext(ctx, http.MethodGet, srv.URL, nil)
+	})
+	if err != nil {
+		t.Fatalf("doWithRetry: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if attempts != 3 {
+		t.Errorf("expected 3 attempts before success, got %d", attempts)
+	}
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("expected final status 200, got %d", resp.StatusCode)
+	}
+}
+
+func TestDoWithRetryGivesUpAfterMaxRetries(t *testing.T) {
+	var attempts int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer srv.Close()
+
+	cfg := retryConfig{MaxRetries: 2, BaseDelay: time.Millisecond}
+	_, err := doWithRetry(context.Background(), srv.Client(), cfg, func(ctx context.Context) (*http.Request, error) {
+		return http.NewRequestWithContext(ctx, http.MethodGet, srv.URL, nil)
+	})
+	if err == nil {
+		t.Fatal("expected an error after exhausting retries")
+	}
+	if attempts != 3 {
+		t.Errorf("expected 1 initial attempt + 2 retries = 3 total, got %d", attempts)
+	}
+}
+
+func TestDoWithRetryDoesNotRetry4xx(t *testing.T) {
+	var attempts int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		w.WriteHeader(http.StatusUnauthorized)
+	}))
+	defer srv.Close()
+
+	cfg := retryConfig{MaxRetries: 5, BaseDelay: time.Millisecond}
+	resp, err := doWithRetry(context.Background(), srv.Client(), cfg, func(ctx context.Context) (*http.Request, error) {
+		return http.NewRequestWithContext(ctx, http.MethodGet, srv.URL, nil)
+	})
+	if err != nil {
+		t.Fatalf("doWithRetry: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if attempts != 1 {
+		t.Errorf("expected a 401 to be returned without retrying, got %d attempts", attempts)
+	}
+	if resp.StatusCode != http.StatusUnauthorized {
+		t.Errorf("expected status 401 to be passed through, got %d", resp.StatusCode)
+	}
+}
+
+func TestDoWithRetryHonorsRetryAfterHeader(t *testing.T) {
+	var attempts int
+	var firstAttempt time.Time
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		if attempts == 1 {
+			firstAttempt = time.Now()
+			w.Header().Set("Retry-After", "1")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+
+	cfg := retryConfig{MaxRetries: 1, BaseDelay: time.Millisecond}
+	resp, err := doWithRetry(context.Background(), srv.Client(), cfg, func(ctx context.Context) (*http.Request, error) {
+		return http.NewRequestWithContext(ctx, http.MethodGet, srv.URL, nil)
+	})
+	if err != nil {
+		t.Fatalf("doWithRetry: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if time.Since(firstAttempt) < time.Second {
+		t.Errorf("expected doWithRetry to wait at least the Retry-After duration")
+	}
+}
+
+func TestDoWithRetryStopsOnContextCancellation(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer srv.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cfg := retryConfig{MaxRetries: 10, BaseDelay: 50 * time.Millisecond}
+
+	go func() {
+		time.Sleep(10 * time.Millisecond)
+		cancel()
+	}()
+
+	_, err := doWithRetry(ctx, srv.Client(), cfg, func(ctx context.Context) (*http.Request, error) {
+		return http.NewRequestWithContext(ctx, http.MethodGet, srv.URL, nil)
+	})
+	if err != context.Canceled {
+		t.Errorf("expected context.Canceled, got %v", err)
+	}
+}
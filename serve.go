@@ -0,0 +1,127 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log"
+	"net/http"
+)
+
+// apiError mirrors the {error:{message,type}} envelope already parsed
+// from chatResponse, plus a code field, so clients of this server see
+// the same {error:{message,type,code}} shape they'd get from the OpenAI
+// API itself.
+type apiError struct {
+	Message string `json:"message"`
+	Type    string `json:"type"`
+	Code    string `json:"code"`
+}
+
+type apiErrorEnvelope struct {
+	Error apiError `json:"error"`
+}
+
+func writeAPIError(w http.ResponseWriter, status int, errType, code, msg string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(apiErrorEnvelope{Error: apiError{Message: msg, Type: errType, Code: code}})
+}
+
+// translationServer exposes a translator over HTTP, both through a
+// custom /v1/translations endpoint and an OpenAI-compatible
+// /v1/chat/completions proxy.
+type translationServer struct {
+	translator  Translator
+	bearerToken string
+	verbose     bool
+}
+
+func runServeCommand(args []string, globalCfg config) error {
+	fs := flag.NewFlagSet("serve", flag.ExitOnError)
+	host := fs.String("host", "0.0.0.0", "Host to bind")
+	port := fs.Int("port", 8080, "Port to bind")
+	uploadLimitMB := fs.Int64("upload-limit-mb", 10, "Maximum request body size, in megabytes")
+	cors := fs.Bool("cors", false, "Enable permissive CORS headers")
+	provider := fs.String("p", "", "Translation provider (openai, azure, deepl, or a LocalAI/Ollama-compatible name)")
+	fs.StringVar(provider, "provider", "", "Translation provider (openai, azure, deepl, or a LocalAI/Ollama-compatible name)")
+	baseURL := fs.String("base-url", "", "Base URL for the provider's API")
+	model := fs.String("model", "", "Model or deployment name to request")
+	apiToken := fs.String("a", "", "API token for the selected provider")
+	verbose := fs.Bool("verbose", false, "Enable verbose mode")
+	timeout := fs.Duration("timeout", 0, "HTTP request timeout (default 30s)")
+	maxRetries := fs.Int("max-retries", -1, "Maximum retry attempts for failed upstream requests (default 5)")
+	retryBaseDelay := fs.Duration("retry-base-delay", 0, "Base delay between retries, doubling each attempt (default 500ms)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	retryOpts := resolveRetryOptions(globalCfg, *timeout, *maxRetries, *retryBaseDelay)
+
+	translator, err := newTranslator(*provider, globalCfg, *apiToken, *baseURL, *model, *verbose, retryOpts)
+	if err != nil {
+		return fmt.Errorf("failed to initialize translator: %w", err)
+	}
+
+	srv := &translationServer{
+		translator:  translator,
+		bearerToken: globalCfg.Serve.BearerToken,
+		verbose:     *verbose,
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/healthz", srv.handleHealthz)
+	mux.HandleFunc("/v1/translations", srv.withAuth(srv.handleTranslations))
+	mux.HandleFunc("/v1/chat/completions", srv.withAuth(srv.handleChatCompletions))
+
+	var handler http.Handler = mux
+	handler = withUploadLimit(handler, *uploadLimitMB*1024*1024)
+	if *cors {
+		handler = withCORS(handler)
+	}
+
+	addr := fmt.Sprintf("%s:%d", *host, *port)
+	log.Printf("translate-cli serve listening on %s\n", addr)
+	return http.ListenAndServe(addr, handler)
+}
+
+func withUploadLimit(next http.Handler, limitBytes int64) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		r.Body = http.MaxBytesReader(w, r.Body, limitBytes)
+		next.ServeHTTP(w, r)
+	})
+}
+
+func withCORS(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Access-Control-Allow-Origin", "*")
+		w.Header().Set("Access-Control-Allow-Methods", "POST, OPTIONS")
+		w.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization")
+		if r.Method == http.MethodOptions {
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+func (s *translationServer) withAuth(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if s.bearerToken == "" {
+			next(w, r)
+			return
+		}
+
+		if r.Header.Get("Authorization") != "Bearer "+s.bearerToken {
+			writeAPIError(w, http.StatusUnauthorized, "invalid_request_error", "invalid_api_key", "incorrect or missing bearer token")
+			return
+		}
+
+		next(w, r)
+	}
+}
+
+func (s *translationServer) handleHealthz(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]string{"status": "ok"})
+}
@@ -0,0 +1,199 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+)
+
+// translationRequest is the request body for the custom
+// POST /v1/translations endpoint.
+type translationRequest struct {
+	From   string `json:"from"`
+	To     string `json:"to"`
+	Text   string `json:"text"`
+	Stream bool   `json:"stream"`
+}
+
+type translationResponse struct {
+	Translation string `json:"translation"`
+}
+
+func (s *translationServer) handleTranslations(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeAPIError(w, http.StatusMethodNotAllowed, "invalid_request_error", "method_not_allowed", "only POST is supported")
+		return
+	}
+
+	var req translationRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeAPIError(w, http.StatusBadRequest, "invalid_request_error", "invalid_json", err.Error())
+		return
+	}
+
+	if req.Text == "" {
+		writeAPIError(w, http.StatusBadRequest, "invalid_request_error", "missing_text", "text is required")
+		return
+	}
+
+	if req.From == "" {
+		req.From = "auto"
+	}
+	if req.To == "" {
+		req.To = "en"
+	}
+
+	if req.Stream {
+		s.streamSSE(r.Context(), w, req.From, req.To, req.Text)
+		return
+	}
+
+	translation, err := s.translator.Translate(r.Context(), req.From, req.To, req.Text)
+	if err != nil {
+		writeAPIError(w, http.StatusBadGateway, "api_error", "translation_failed", err.Error())
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(translationResponse{Translation: translation})
+}
+
+// handleChatCompletions rewrites an OpenAI-style chat completions
+// request into a translation: the last user message is the text to
+// translate, and the source/target languages come from the
+// X-From-Lang/X-To-Lang headers (defaulting to "auto" and "en") so any
+// OpenAI SDK can point its base URL at this server unmodified.
+func (s *translationServer) handleChatCompletions(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeAPIError(w, http.StatusMethodNotAllowed, "invalid_request_error", "method_not_allowed", "only POST is supported")
+		return
+	}
+
+	var req chatRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeAPIError(w, http.StatusBadRequest, "invalid_request_error", "invalid_json", err.Error())
+		return
+	}
+
+	text := lastUserMessage(req.Messages)
+	if text == "" {
+		writeAPIError(w, http.StatusBadRequest, "invalid_request_error", "missing_text", "no user message found in messages")
+		return
+	}
+
+	fromLang := headerOrDefault(r, "X-From-Lang", "auto")
+	toLang := headerOrDefault(r, "X-To-Lang", "en")
+
+	if req.Stream {
+		s.streamSSE(r.Context(), w, fromLang, toLang, text)
+		return
+	}
+
+	translation, err := s.translator.Translate(r.Context(), fromLang, toLang, text)
+	if err != nil {
+		writeAPIError(w, http.StatusBadGateway, "api_error", "translation_failed", err.Error())
+		return
+	}
+
+	resp := chatResponse{}
+	resp.Choices = []struct {
+		Message message `json:"message"`
+	}{{Message: message{Role: "assistant", Content: translation}}}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(resp)
+}
+
+func lastUserMessage(messages []message) string {
+	for i := len(messages) - 1; i >= 0; i-- {
+		if messages[i].Role == "user" {
+			return messages[i].Content
+		}
+	}
+	return ""
+}
+
+func headerOrDefault(r *http.Request, header, fallback string) string {
+	if v := r.Header.Get(header); v != "" {
+		return v
+	}
+	return fallback
+}
+
+// streamSSE translates text and relays it to w as OpenAI-style
+// "chat.completion.chunk" SSE frames, token by token, ending with the
+// [DONE] sentinel real clients expect.
+func (s *translationServer) streamSSE(ctx context.Context, w http.ResponseWriter, fromLang, toLang, text string) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		writeAPIError(w, http.StatusInternalServerError, "api_error", "streaming_unsupported", "response writer does not support flushing")
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+
+	sw := newSSETokenWriter(w, flusher)
+	if _, err := s.translator.TranslateStream(ctx, fromLang, toLang, text, sw); err != nil {
+		sw.writeError(err)
+	}
+	fmt.Fprint(w, "data: [DONE]\n\n")
+	flusher.Flush()
+}
+
+// sseTokenWriter adapts the plain-text token stream Translator.TranslateStream
+// writes into OpenAI-style "data: {...}\n\n" chat completion chunks.
+type sseTokenWriter struct {
+	w       *bufio.Writer
+	flusher http.Flusher
+}
+
+func newSSETokenWriter(w http.ResponseWriter, flusher http.Flusher) *sseTokenWriter {
+	return &sseTokenWriter{w: bufio.NewWriter(w), flusher: flusher}
+}
+
+func (sw *sseTokenWriter) Write(p []byte) (int, error) {
+	chunk := struct {
+		Choices []struct {
+			Delta struct {
+				Content string `json:"content"`
+			} `json:"delta"`
+		} `json:"choices"`
+	}{}
+	chunk.Choices = []struct {
+		Delta struct {
+			Content string `json:"content"`
+		} `json:"delta"`
+	}{{}}
+	chunk.Choices[0].Delta.Content = string(p)
+
+	data, err := json.Marshal(chunk)
+	if err != nil {
+		return 0, err
+	}
+
+	if _, err := fmt.Fprintf(sw.w, "data: %s\n\n", data); err != nil {
+		return 0, err
+	}
+	sw.w.Flush()
+	sw.flusher.Flush()
+
+	return len(p), nil
+}
+
+func (sw *sseTokenWriter) writeError(err error) {
+	fmt.Fprintf(sw.w, "data: %s\n\n", mustMarshalSSEError(err))
+	sw.w.Flush()
+	sw.flusher.Flush()
+}
+
+func mustMarshalSSEError(err error) []byte {
+	data, marshalErr := json.Marshal(apiErrorEnvelope{Error: apiError{Message: err.Error(), Type: "api_error"}})
+	if marshalErr != nil {
+		return []byte(`{"error":{"message":"translation failed","type":"api_error"}}`)
+	}
+	return data
+}
@@ -0,0 +1,261 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// streamingFakeTranslator writes its translation one token at a time so
+// SSE framing can be observed, mirroring fakeTranslator in batch_test.go.
+type streamingFakeTranslator struct{}
+
+func (streamingFakeTranslator) Translate(ctx context.Context, fromLang, toLang, text string) (string, error) {
+	return strings.ToUpper(text), nil
+}
+
+func (streamingFakeTranslator) TranslateStream(ctx context.Context, fromLang, toLang, text string, w io.Writer) (string, error) {
+	result := strings.ToUpper(text)
+	for _, tok := range strings.Fields(result) {
+		io.WriteString(w, tok+" ")
+	}
+	return result, nil
+}
+
+func newTestServer(bearerToken string) (*translationServer, *httptest.Server) {
+	srv := &translationServer{translator: streamingFakeTranslator{}, bearerToken: bearerToken}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/healthz", srv.handleHealthz)
+	mux.HandleFunc("/v1/translations", srv.withAuth(srv.handleTranslations))
+	mux.HandleFunc("/v1/chat/completions", srv.withAuth(srv.handleChatCompletions))
+
+	return srv, httptest.NewServer(mux)
+}
+
+func TestWithAuthRejectsMissingOrWrongToken(t *testing.T) {
+	_, ts := newTestServer("secret")
+	defer ts.Close()
+
+	body := strings.NewReader(`{"from":"en","to":"fr","text":"hi"}`)
+	req, _ := http.NewRequest(http.MethodPost, ts.URL+"/v1/translations", body)
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("request: %v", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusUnauthorized {
+		t.Errorf("status = %d, want %d", resp.StatusCode, http.StatusUnauthorized)
+	}
+
+	req2, _ := http.NewRequest(http.MethodPost, ts.URL+"/v1/translations", strings.NewReader(`{"from":"en","to":"fr","text":"hi"}`))
+	req2.Header.Set("Authorization", "Bearer wrong")
+	resp2, err := http.DefaultClient.Do(req2)
+	if err != nil {
+		t.Fatalf("request: %v", err)
+	}
+	defer resp2.Body.Close()
+	if resp2.StatusCode != http.StatusUnauthorized {
+		t.Errorf("status = %d, want %d", resp2.StatusCode, http.StatusUnauthorized)
+	}
+}
+
+func TestWithAuthAcceptsCorrectToken(t *testing.T) {
+	_, ts := newTestServer("secret")
+	defer ts.Close()
+
+	req, _ := http.NewRequest(http.MethodPost, ts.URL+"/v1/translations", strings.NewReader(`{"from":"en","to":"fr","text":"hi"}`))
+	req.Header.Set("Authorization", "Bearer secret")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("request: %v", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("status = %d, want %d", resp.StatusCode, http.StatusOK)
+	}
+}
+
+func TestWithAuthAllowsAllWhenNoTokenConfigured(t *testing.T) {
+	_, ts := newTestServer("")
+	defer ts.Close()
+
+	resp, err := http.Post(ts.URL+"/v1/translations", "application/json", strings.NewReader(`{"from":"en","to":"fr","text":"hi"}`))
+	if err != nil {
+		t.Fatalf("request: %v", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("status = %d, want %d", resp.StatusCode, http.StatusOK)
+	}
+}
+
+func TestHealthzBypassesAuth(t *testing.T) {
+	_, ts := newTestServer("secret")
+	defer ts.Close()
+
+	resp, err := http.Get(ts.URL + "/healthz")
+	if err != nil {
+		t.Fatalf("request: %v", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("status = %d, want %d", resp.StatusCode, http.StatusOK)
+	}
+
+	var body map[string]string
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if body["status"] != "ok" {
+		t.Errorf("status body = %+v, want status=ok", body)
+	}
+}
+
+func TestLastUserMessage(t *testing.T) {
+	cases := []struct {
+		name     string
+		messages []message
+		want     string
+	}{
+		{"empty", nil, ""},
+		{"single user", []message{{Role: "user", Content: "hi"}}, "hi"},
+		{
+			"picks the last user message, skipping assistant turns",
+			[]message{
+				{Role: "system", Content: "sys"},
+				{Role: "user", Content: "first"},
+				{Role: "assistant", Content: "reply"},
+				{Role: "user", Content: "second"},
+			},
+			"second",
+		},
+		{"no user messages", []message{{Role: "system", Content: "sys"}}, ""},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if got := lastUserMessage(c.messages); got != c.want {
+				t.Errorf("lastUserMessage(%+v) = %q, want %q", c.messages, got, c.want)
+			}
+		})
+	}
+}
+
+func TestHandleTranslationsStreamsSSEFrames(t *testing.T) {
+	_, ts := newTestServer("")
+	defer ts.Close()
+
+	resp, err := http.Post(ts.URL+"/v1/translations", "application/json", strings.NewReader(`{"from":"en","to":"fr","text":"hello world","stream":true}`))
+	if err != nil {
+		t.Fatalf("request: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if ct := resp.Header.Get("Content-Type"); ct != "text/event-stream" {
+		t.Errorf("Content-Type = %q, want text/event-stream", ct)
+	}
+
+	var tokens []string
+	sawDone := false
+	scanner := bufio.NewScanner(resp.Body)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || !strings.HasPrefix(line, "data:") {
+			continue
+		}
+		payload := strings.TrimSpace(strings.TrimPrefix(line, "data:"))
+		if payload == "[DONE]" {
+			sawDone = true
+			continue
+		}
+
+		var chunk struct {
+			Choices []struct {
+				Delta struct {
+					Content string `json:"content"`
+				} `json:"delta"`
+			} `json:"choices"`
+		}
+		if err := json.Unmarshal([]byte(payload), &chunk); err != nil {
+			t.Fatalf("unmarshal %q: %v", payload, err)
+		}
+		tokens = append(tokens, chunk.Choices[0].Delta.Content)
+	}
+
+	if !sawDone {
+		t.Error("expected a final data: [DONE] frame")
+	}
+	got := strings.Join(tokens, "")
+	if got != "HELLO WORLD " {
+		t.Errorf("streamed tokens = %q, want %q", got, "HELLO WORLD ")
+	}
+}
+
+func TestHandleChatCompletionsStreamsSSEFrames(t *testing.T) {
+	_, ts := newTestServer("")
+	defer ts.Close()
+
+	reqBody := `{"model":"gpt-4o-mini","messages":[{"role":"user","content":"hello"}],"stream":true}`
+	req, _ := http.NewRequest(http.MethodPost, ts.URL+"/v1/chat/completions", strings.NewReader(reqBody))
+	req.Header.Set("X-From-Lang", "en")
+	req.Header.Set("X-To-Lang", "fr")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("request: %v", err)
+	}
+	defer resp.Body.Close()
+
+	var tokens []string
+	sawDone := false
+	scanner := bufio.NewScanner(resp.Body)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || !strings.HasPrefix(line, "data:") {
+			continue
+		}
+		payload := strings.TrimSpace(strings.TrimPrefix(line, "data:"))
+		if payload == "[DONE]" {
+			sawDone = true
+			continue
+		}
+
+		var chunk struct {
+			Choices []struct {
+				Delta struct {
+					Content string `json:"content"`
+				} `json:"delta"`
+			} `json:"choices"`
+		}
+		if err := json.Unmarshal([]byte(payload), &chunk); err != nil {
+			t.Fatalf("unmarshal %q: %v", payload, err)
+		}
+		tokens = append(tokens, chunk.Choices[0].Delta.Content)
+	}
+
+	if !sawDone {
+		t.Error("expected a final data: [DONE] frame")
+	}
+	if got := strings.Join(tokens, ""); got != "HELLO " {
+		t.Errorf("streamed tokens = %q, want %q", got, "HELLO ")
+	}
+}
+
+func TestHandleChatCompletionsRejectsMissingUserMessage(t *testing.T) {
+	_, ts := newTestServer("")
+	defer ts.Close()
+
+	resp, err := http.Post(ts.URL+"/v1/chat/completions", "application/json", strings.NewReader(`{"model":"gpt-4o-mini","messages":[{"role":"system","content":"sys"}]}`))
+	if err != nil {
+		t.Fatalf("request: %v", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusBadRequest {
+		t.Errorf("status = %d, want %d", resp.StatusCode, http.StatusBadRequest)
+	}
+}
@@ -0,0 +1,58 @@
+package main
+
+import (
+	"regexp"
+	"strings"
+)
+
+var timestampLineRe = regexp.MustCompile(`-->`)
+
+// srtFormat and vttFormat both lay subtitles out as blocks of an index
+// (SRT only), a timestamp line, and one or more text lines, separated by
+// blank lines. Only the text lines are translatable.
+type srtFormat struct{}
+type vttFormat struct{}
+
+func (srtFormat) Parse(data []byte) ([]segment, error)  { return parseSubtitles(data) }
+func (srtFormat) Render(segs []segment) ([]byte, error) { return renderSubtitles(segs), nil }
+
+func (vttFormat) Parse(data []byte) ([]segment, error)  { return parseSubtitles(data) }
+func (vttFormat) Render(segs []segment) ([]byte, error) { return renderSubtitles(segs), nil }
+
+func parseSubtitles(data []byte) ([]segment, error) {
+	lines := strings.Split(string(data), "\n")
+	segs := make([]segment, 0, len(lines))
+
+	for _, line := range lines {
+		trimmed := strings.TrimRight(line, "\r")
+		switch {
+		case trimmed == "", strings.HasPrefix(trimmed, "WEBVTT"), isSubtitleIndexLine(trimmed), timestampLineRe.MatchString(trimmed):
+			segs = append(segs, segment{Text: line, Translatable: false})
+		default:
+			segs = append(segs, segment{Text: line, Translatable: true})
+		}
+	}
+
+	return segs, nil
+}
+
+// isSubtitleIndexLine reports whether line is a bare cue index, e.g. "42".
+func isSubtitleIndexLine(line string) bool {
+	if line == "" {
+		return false
+	}
+	for _, r := range line {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+func renderSubtitles(segs []segment) []byte {
+	lines := make([]string, len(segs))
+	for i, s := range segs {
+		lines[i] = s.Text
+	}
+	return []byte(strings.Join(lines, "\n"))
+}
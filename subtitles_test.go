@@ -0,0 +1,67 @@
+package main
+
+import "testing"
+
+func TestSRTParseMarksOnlyTextLinesTranslatable(t *testing.T) {
+	data := []byte(
+		"1\n" +
+			"00:00:01,000 --> 00:00:02,000\n" +
+			"Hello there\n" +
+			"\n" +
+			"2\n" +
+			"00:00:02,500 --> 00:00:03,500\n" +
+			"General Kenobi\n",
+	)
+
+	segs, err := srtFormat{}.Parse(data)
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+
+	want := []bool{false, false, true, false, false, false, true, false}
+	if len(segs) != len(want) {
+		t.Fatalf("got %d segments, want %d: %+v", len(segs), len(want), segs)
+	}
+	for i, w := range want {
+		if segs[i].Translatable != w {
+			t.Errorf("segment %d (%q): Translatable = %v, want %v", i, segs[i].Text, segs[i].Translatable, w)
+		}
+	}
+}
+
+func TestVTTParseSkipsHeaderAndTimestamps(t *testing.T) {
+	data := []byte("WEBVTT\n\n00:00:01.000 --> 00:00:02.000\nHello there\n")
+
+	segs, err := vttFormat{}.Parse(data)
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+
+	want := []bool{false, false, false, true, false}
+	if len(segs) != len(want) {
+		t.Fatalf("got %d segments, want %d: %+v", len(segs), len(want), segs)
+	}
+	for i, w := range want {
+		if segs[i].Translatable != w {
+			t.Errorf("segment %d (%q): Translatable = %v, want %v", i, segs[i].Text, segs[i].Translatable, w)
+		}
+	}
+}
+
+func TestRenderSubtitlesRoundTrip(t *testing.T) {
+	data := []byte("1\n00:00:01,000 --> 00:00:02,000\nHello there\n")
+
+	segs, err := srtFormat{}.Parse(data)
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+
+	out, err := srtFormat{}.Render(segs)
+	if err != nil {
+		t.Fatalf("Render: %v", err)
+	}
+
+	if string(out) != string(data) {
+		t.Errorf("Render round trip mismatch:\ngot:  %q\nwant: %q", out, data)
+	}
+}
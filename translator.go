@@ -0,0 +1,109 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"time"
+)
+
+const defaultChatModel = "gpt-4o-mini"
+
+// Translator translates text from one language to another using a
+// specific backend. ctx bounds the request: canceling it (e.g. on
+// SIGINT) aborts an in-flight translation or retry wait.
+type Translator interface {
+	Translate(ctx context.Context, fromLang, toLang, text string) (string, error)
+	TranslateStream(ctx context.Context, fromLang, toLang, text string, w io.Writer) (string, error)
+}
+
+// chatHistoryTranslator is implemented by backends that can carry a full
+// conversation history across turns, so the interactive REPL can use it
+// for multi-turn context instead of translating each line in isolation.
+type chatHistoryTranslator interface {
+	ChatTranslate(ctx context.Context, history []message) (string, error)
+	ChatTranslateStream(ctx context.Context, history []message, w io.Writer) (string, error)
+}
+
+// retryOptions bounds how hard a Translator retries a failing request
+// before giving up, shared by every backend.
+type retryOptions struct {
+	Timeout    time.Duration
+	MaxRetries int
+	BaseDelay  time.Duration
+}
+
+// newTranslator builds the Translator for the named provider. Settings
+// are resolved in order of precedence: CLI flags, then the matching
+// `providers:` entry in cfg, then cfg's top-level defaults. An empty
+// name falls back to cfg.DefaultProvider, then "openai".
+func newTranslator(name string, cfg config, apiToken, baseURL, model string, verbose bool, retry retryOptions) (Translator, error) {
+	if name == "" {
+		name = cfg.DefaultProvider
+	}
+	if name == "" {
+		name = "openai"
+	}
+
+	provider := cfg.Providers[name]
+	if apiToken == "" {
+		apiToken = provider.APIToken
+	}
+	if apiToken == "" {
+		apiToken = cfg.APIToken
+	}
+	if baseURL == "" {
+		baseURL = provider.BaseURL
+	}
+	modelFlag := model
+	if model == "" {
+		model = provider.Model
+	}
+
+	switch name {
+	case "openai":
+		if apiToken == "" {
+			return nil, fmt.Errorf("missing API token for provider %q", name)
+		}
+		if model == "" {
+			model = defaultChatModel
+		}
+		return newOpenAITranslator(apiToken, model, verbose, retry.Timeout, retry.MaxRetries, retry.BaseDelay), nil
+
+	case "azure":
+		if apiToken == "" {
+			return nil, fmt.Errorf("missing API token for provider %q", name)
+		}
+		// Azure addresses a deployment rather than a model, so --model
+		// names the deployment, overriding the config's deployment key.
+		deployment := provider.Deployment
+		if modelFlag != "" {
+			deployment = modelFlag
+		}
+		if baseURL == "" || deployment == "" {
+			return nil, fmt.Errorf("azure provider %q requires base_url and deployment", name)
+		}
+		apiVersion := provider.APIVersion
+		if apiVersion == "" {
+			apiVersion = "2024-02-01"
+		}
+		return newAzureTranslator(baseURL, apiToken, deployment, apiVersion, verbose, retry.Timeout, retry.MaxRetries, retry.BaseDelay), nil
+
+	case "deepl":
+		if apiToken == "" {
+			return nil, fmt.Errorf("missing API token for provider %q", name)
+		}
+		return newDeepLTranslator(apiToken, baseURL, verbose, retry.Timeout, retry.MaxRetries, retry.BaseDelay), nil
+
+	default:
+		// Anything else is treated as an OpenAI-compatible endpoint
+		// (LocalAI, Ollama, vLLM, ...).
+		if baseURL == "" {
+			return nil, fmt.Errorf("provider %q requires --base-url (e.g. a LocalAI or Ollama endpoint)", name)
+		}
+		if model == "" {
+			return nil, fmt.Errorf("provider %q requires --model", name)
+		}
+		return newCompatibleTranslator(baseURL, apiToken, model, verbose, retry.Timeout, retry.MaxRetries, retry.BaseDelay), nil
+	}
+}
@@ -0,0 +1,72 @@
+package main
+
+import "testing"
+
+func TestNewTranslatorRejectsMissingAPIToken(t *testing.T) {
+	cases := []struct {
+		name     string
+		provider string
+		cfg      config
+	}{
+		{"openai", "openai", config{}},
+		{"azure", "azure", config{Providers: map[string]providerConfig{
+			"azure": {BaseURL: "https://example.openai.azure.com", Deployment: "gpt-4o"},
+		}}},
+		{"deepl", "deepl", config{}},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			_, err := newTranslator(c.provider, c.cfg, "", "", "", false, retryOptions{})
+			if err == nil {
+				t.Fatalf("expected an error for provider %q with no API token", c.provider)
+			}
+		})
+	}
+}
+
+func TestNewTranslatorAzureRequiresBaseURLAndDeployment(t *testing.T) {
+	_, err := newTranslator("azure", config{}, "token", "", "", false, retryOptions{})
+	if err == nil {
+		t.Fatal("expected an error when base_url and deployment are missing")
+	}
+}
+
+func TestNewTranslatorCompatibleRequiresBaseURLAndModel(t *testing.T) {
+	if _, err := newTranslator("localai", config{}, "", "", "llama3", false, retryOptions{}); err == nil {
+		t.Fatal("expected an error when --base-url is missing")
+	}
+	if _, err := newTranslator("localai", config{}, "", "http://localhost:8080", "", false, retryOptions{}); err == nil {
+		t.Fatal("expected an error when --model is missing")
+	}
+}
+
+func TestNewTranslatorDefaultsToOpenAI(t *testing.T) {
+	translator, err := newTranslator("", config{}, "token", "", "", false, retryOptions{})
+	if err != nil {
+		t.Fatalf("newTranslator: %v", err)
+	}
+	if _, ok := translator.(*openAITranslator); !ok {
+		t.Fatalf("expected an *openAITranslator, got %T", translator)
+	}
+}
+
+func TestNewTranslatorResolvesProviderConfigDefaults(t *testing.T) {
+	cfg := config{
+		Providers: map[string]providerConfig{
+			"azure": {
+				APIToken:   "from-config",
+				BaseURL:    "https://example.openai.azure.com",
+				Deployment: "gpt-4o",
+			},
+		},
+	}
+
+	translator, err := newTranslator("azure", cfg, "", "", "", false, retryOptions{})
+	if err != nil {
+		t.Fatalf("newTranslator: %v", err)
+	}
+	if _, ok := translator.(*azureTranslator); !ok {
+		t.Fatalf("expected an *azureTranslator, got %T", translator)
+	}
+}
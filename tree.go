@@ -0,0 +1,123 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"gopkg.in/yaml.v2"
+)
+
+// jsonTreeFormat and yamlTreeFormat translate every string leaf in an
+// arbitrary i18n JSON/YAML document (e.g. `{"greeting": {"hello": "Hi"}}`)
+// while leaving keys, numbers, booleans, and nesting untouched.
+type jsonTreeFormat struct{ root interface{} }
+type yamlTreeFormat struct{ root interface{} }
+
+func (f *jsonTreeFormat) Parse(data []byte) ([]segment, error) {
+	if err := json.Unmarshal(data, &f.root); err != nil {
+		return nil, fmt.Errorf("failed to parse JSON: %w", err)
+	}
+	return collectLeaves(f.root), nil
+}
+
+func (f *jsonTreeFormat) Render(segs []segment) ([]byte, error) {
+	queue := append([]segment(nil), segs...)
+	rebuilt := rebuildLeaves(f.root, &queue)
+	return json.MarshalIndent(rebuilt, "", "  ")
+}
+
+func (f *yamlTreeFormat) Parse(data []byte) ([]segment, error) {
+	if err := yaml.Unmarshal(data, &f.root); err != nil {
+		return nil, fmt.Errorf("failed to parse YAML: %w", err)
+	}
+	return collectLeaves(f.root), nil
+}
+
+func (f *yamlTreeFormat) Render(segs []segment) ([]byte, error) {
+	queue := append([]segment(nil), segs...)
+	rebuilt := rebuildLeaves(f.root, &queue)
+	return yaml.Marshal(rebuilt)
+}
+
+// collectLeaves walks node (as produced by encoding/json or
+// gopkg.in/yaml.v2 into interface{}) and returns every string leaf as a
+// translatable segment, visiting map keys in sorted order so the walk is
+// deterministic and rebuildLeaves can consume the same sequence back.
+func collectLeaves(node interface{}) []segment {
+	var segs []segment
+
+	var walk func(interface{})
+	walk = func(n interface{}) {
+		switch v := n.(type) {
+		case string:
+			segs = append(segs, segment{Text: v, Translatable: true})
+		case map[string]interface{}:
+			for _, k := range sortedStringKeys(v) {
+				walk(v[k])
+			}
+		case map[interface{}]interface{}:
+			for _, k := range sortedInterfaceKeys(v) {
+				walk(v[k])
+			}
+		case []interface{}:
+			for _, item := range v {
+				walk(item)
+			}
+		}
+	}
+	walk(node)
+
+	return segs
+}
+
+// rebuildLeaves walks node in the same order as collectLeaves, replacing
+// each string leaf with the next segment's (translated) text.
+func rebuildLeaves(node interface{}, segs *[]segment) interface{} {
+	switch v := node.(type) {
+	case string:
+		next := (*segs)[0]
+		*segs = (*segs)[1:]
+		return next.Text
+	case map[string]interface{}:
+		out := make(map[string]interface{}, len(v))
+		for _, k := range sortedStringKeys(v) {
+			out[k] = rebuildLeaves(v[k], segs)
+		}
+		return out
+	case map[interface{}]interface{}:
+		out := make(map[interface{}]interface{}, len(v))
+		for _, k := range sortedInterfaceKeys(v) {
+			out[k] = rebuildLeaves(v[k], segs)
+		}
+		return out
+	case []interface{}:
+		out := make([]interface{}, len(v))
+		for i, item := range v {
+			out[i] = rebuildLeaves(item, segs)
+		}
+		return out
+	default:
+		return v
+	}
+}
+
+func sortedStringKeys(m map[string]interface{}) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func sortedInterfaceKeys(m map[interface{}]interface{}) []interface{} {
+	keys := make([]interface{}, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		return fmt.Sprint(keys[i]) < fmt.Sprint(keys[j])
+	})
+	return keys
+}
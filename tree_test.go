@@ -0,0 +1,62 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestJSONTreeFormatRoundTripsStructure(t *testing.T) {
+	data := []byte(`{"greeting": {"hello": "Hi", "bye": "Bye"}, "count": 3, "tags": ["a", "b"]}`)
+
+	f := &jsonTreeFormat{}
+	segs, err := f.Parse(data)
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+
+	for i := range segs {
+		segs[i].Text = strings.ToUpper(segs[i].Text)
+	}
+
+	out, err := f.Render(segs)
+	if err != nil {
+		t.Fatalf("Render: %v", err)
+	}
+
+	rendered := string(out)
+	for _, want := range []string{`"HI"`, `"BYE"`, `"A"`, `"B"`, `"count": 3`} {
+		if !strings.Contains(rendered, want) {
+			t.Errorf("rendered JSON missing %q:\n%s", want, rendered)
+		}
+	}
+}
+
+func TestYAMLTreeFormatLeavesKeysAlone(t *testing.T) {
+	data := []byte("greeting:\n  hello: Hi\ncount: 3\n")
+
+	f := &yamlTreeFormat{}
+	segs, err := f.Parse(data)
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+
+	var translated int
+	for i := range segs {
+		if segs[i].Translatable {
+			segs[i].Text = strings.ToUpper(segs[i].Text)
+			translated++
+		}
+	}
+	if translated != 1 {
+		t.Fatalf("expected exactly one translatable leaf, got %d", translated)
+	}
+
+	out, err := f.Render(segs)
+	if err != nil {
+		t.Fatalf("Render: %v", err)
+	}
+
+	if !strings.Contains(string(out), "HI") || !strings.Contains(string(out), "greeting:") {
+		t.Errorf("rendered YAML missing expected content:\n%s", out)
+	}
+}